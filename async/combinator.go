@@ -0,0 +1,201 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package async
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/tforce-io/tf-golib/concurrent"
+)
+
+// ErrNoFutures is returned by combinators requiring at least one Future when none is given.
+//
+// Available since v0.11.0
+var ErrNoFutures = errors.New("no futures")
+
+// ErrNilFuture is returned by Then when its function returns a nil Future.
+//
+// Available since v0.11.0
+var ErrNilFuture = errors.New("nil future")
+
+// Return a Future of fn applied on the value of f, run in a new goroutine once f is resolved.
+// If f is rejected, the returned Future is rejected with the same error and fn is not called.
+// A panic in fn rejects the returned Future with an error wrapping ErrPanicked.
+// Cancelling the returned Future cancels f.
+//
+// Available since v0.11.0
+func Map[T, R any](f *Future[T], fn func(value T) (R, error)) *Future[R] {
+	p := NewPromise[R]()
+	p.OnCancel(func() { f.Cancel() })
+	f.onComplete(func() {
+		if f.err != nil {
+			p.Reject(f.err)
+			return
+		}
+		go func() {
+			p.Complete(call(fn, f.value))
+		}()
+	})
+	return p.Future()
+}
+
+// Return a Future completed with the outcome of the Future returned by fn,
+// which is called in a new goroutine once f is resolved.
+// If f is rejected, the returned Future is rejected with the same error and fn is not called.
+// A panic in fn rejects the returned Future with an error wrapping ErrPanicked.
+// A nil Future returned by fn rejects the returned Future with ErrNilFuture.
+// Cancelling the returned Future cancels f and the Future returned by fn.
+//
+// Available since v0.11.0
+func Then[T, R any](f *Future[T], fn func(value T) *Future[R]) *Future[R] {
+	p := NewPromise[R]()
+	var mu sync.Mutex
+	var next *Future[R]
+	p.OnCancel(func() {
+		f.Cancel()
+		mu.Lock()
+		defer mu.Unlock()
+		if next != nil {
+			next.Cancel()
+		}
+	})
+	f.onComplete(func() {
+		if f.err != nil {
+			p.Reject(f.err)
+			return
+		}
+		go func() {
+			inner, err := call(func(value T) (*Future[R], error) {
+				return fn(value), nil
+			}, f.value)
+			if err != nil {
+				p.Reject(err)
+				return
+			}
+			if inner == nil {
+				p.Reject(ErrNilFuture)
+				return
+			}
+			mu.Lock()
+			next = inner
+			mu.Unlock()
+			if p.future.isCancelled() {
+				inner.Cancel()
+				return
+			}
+			inner.onComplete(func() {
+				p.Complete(inner.value, inner.err)
+			})
+		}()
+	})
+	return p.Future()
+}
+
+// Return a Future resolved with the values of all futures in the same order,
+// or rejected with the first error. Cancelling the returned Future cancels all futures.
+//
+// Available since v0.11.0
+func All[T any](futures ...*Future[T]) *Future[[]T] {
+	if len(futures) == 0 {
+		return Resolved([]T{})
+	}
+	p := NewPromise[[]T]()
+	p.OnCancel(func() { cancelAll(futures) })
+	values := make([]T, len(futures))
+	var mu sync.Mutex
+	remaining := len(futures)
+	for i, f := range futures {
+		i, f := i, f
+		f.onComplete(func() {
+			if f.err != nil {
+				p.Reject(f.err)
+				return
+			}
+			mu.Lock()
+			values[i] = f.value
+			remaining--
+			done := remaining == 0
+			mu.Unlock()
+			if done {
+				p.Resolve(values)
+			}
+		})
+	}
+	return p.Future()
+}
+
+// Return a Future resolved with the value of the first resolved future.
+// If all futures are rejected, it is rejected with *concurrent.MultiError of their errors in the same order.
+// Cancelling the returned Future cancels all futures.
+//
+// Available since v0.11.0
+func Any[T any](futures ...*Future[T]) *Future[T] {
+	if len(futures) == 0 {
+		return Rejected[T](ErrNoFutures)
+	}
+	p := NewPromise[T]()
+	p.OnCancel(func() { cancelAll(futures) })
+	errs := make([]error, len(futures))
+	var mu sync.Mutex
+	remaining := len(futures)
+	for i, f := range futures {
+		i, f := i, f
+		f.onComplete(func() {
+			if f.err == nil {
+				p.Resolve(f.value)
+				return
+			}
+			mu.Lock()
+			errs[i] = f.err
+			remaining--
+			done := remaining == 0
+			mu.Unlock()
+			if done {
+				p.Reject(&concurrent.MultiError{Errors: errs})
+			}
+		})
+	}
+	return p.Future()
+}
+
+// Return a Future completed with the outcome of the first completed future, whether resolved or rejected.
+// Cancelling the returned Future cancels all futures.
+//
+// Available since v0.11.0
+func Race[T any](futures ...*Future[T]) *Future[T] {
+	if len(futures) == 0 {
+		return Rejected[T](ErrNoFutures)
+	}
+	p := NewPromise[T]()
+	p.OnCancel(func() { cancelAll(futures) })
+	for _, f := range futures {
+		f := f
+		f.onComplete(func() {
+			p.Complete(f.value, f.err)
+		})
+	}
+	return p.Future()
+}
+
+// Cancel all futures.
+func cancelAll[T any](futures []*Future[T]) {
+	for _, f := range futures {
+		f.Cancel()
+	}
+}
+
+// Call fn and convert a panic into an error.
+func call[T, R any](fn func(value T) (R, error), value T) (result R, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanicked, r)
+		}
+	}()
+	return fn(value)
+}
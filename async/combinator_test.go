@@ -0,0 +1,144 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package async
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/concurrent"
+)
+
+func after[T any](delay time.Duration, value T, err error) *Future[T] {
+	return Run(context.Background(), func(ctx context.Context) (T, error) {
+		select {
+		case <-time.After(delay):
+			return value, err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	})
+}
+
+func TestMap(t *testing.T) {
+	f := Map(Resolved(21), func(value int) (string, error) {
+		return strconv.Itoa(value * 2), nil
+	})
+	value, err := f.Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "42", value)
+
+	boom := errors.New("boom")
+	called := false
+	f = Map(Rejected[int](boom), func(value int) (string, error) {
+		called = true
+		return "", nil
+	})
+	_, err = f.Await(context.Background())
+	assert.ErrorIs(t, err, boom)
+	assert.False(t, called)
+
+	f = Map(Resolved(1), func(value int) (string, error) {
+		panic("boom")
+	})
+	_, err = f.Await(context.Background())
+	assert.ErrorIs(t, err, ErrPanicked)
+}
+
+func TestMap_Cancel(t *testing.T) {
+	source := NewPromise[int]()
+	mapped := Map(source.Future(), func(value int) (int, error) {
+		return value, nil
+	})
+
+	mapped.Cancel()
+	_, err := source.Future().Await(context.Background())
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestThen(t *testing.T) {
+	f := Then(Resolved(2), func(value int) *Future[int] {
+		return after(time.Millisecond, value*10, nil)
+	})
+	value, err := f.Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 20, value)
+
+	inner := NewPromise[int]()
+	f = Then(Resolved(1), func(value int) *Future[int] {
+		return inner.Future()
+	})
+	time.Sleep(10 * time.Millisecond)
+	f.Cancel()
+	_, err = inner.Future().Await(context.Background())
+	assert.ErrorIs(t, err, context.Canceled)
+
+	f = Then(Resolved(1), func(value int) *Future[int] {
+		return nil
+	})
+	_, err = f.Await(context.Background())
+	assert.ErrorIs(t, err, ErrNilFuture)
+}
+
+func TestAll(t *testing.T) {
+	values, err := All(after(3*time.Millisecond, 1, nil), Resolved(2), after(time.Millisecond, 3, nil)).Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, values)
+
+	values, err = All[int]().Await(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+
+	boom := errors.New("boom")
+	slow := after(time.Second, 1, nil)
+	_, err = All(slow, after(time.Millisecond, 0, boom)).Await(context.Background())
+	assert.ErrorIs(t, err, boom)
+	slow.Cancel()
+}
+
+func TestAny(t *testing.T) {
+	boom := errors.New("boom")
+	value, err := Any(after(time.Millisecond, 0, boom), after(5*time.Millisecond, 2, nil)).Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	_, err = Any(Rejected[int](boom), Rejected[int](context.Canceled)).Await(context.Background())
+	var multi *concurrent.MultiError
+	assert.ErrorAs(t, err, &multi)
+	assert.Equal(t, []error{boom, context.Canceled}, multi.Errors)
+
+	_, err = Any[int]().Await(context.Background())
+	assert.ErrorIs(t, err, ErrNoFutures)
+}
+
+func TestRace(t *testing.T) {
+	boom := errors.New("boom")
+	_, err := Race(after(time.Millisecond, 0, boom), after(time.Second, 2, nil)).Await(context.Background())
+	assert.ErrorIs(t, err, boom)
+
+	value, err := Race(after(time.Second, 1, nil), Resolved(2)).Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	_, err = Race[int]().Await(context.Background())
+	assert.ErrorIs(t, err, ErrNoFutures)
+}
+
+func TestCombinator_CancelPropagates(t *testing.T) {
+	a, b := NewPromise[int](), NewPromise[int]()
+	All(a.Future(), b.Future()).Cancel()
+
+	_, errA := a.Future().Await(context.Background())
+	_, errB := b.Future().Await(context.Background())
+	assert.ErrorIs(t, errA, context.Canceled)
+	assert.ErrorIs(t, errB, context.Canceled)
+}
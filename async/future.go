@@ -0,0 +1,245 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrPanicked is wrapped by the error of a Future whose producing function panicked.
+//
+// Available since v0.11.0
+var ErrPanicked = errors.New("future panicked")
+
+// Struct Future is the read side of a result that will be available later.
+// A Future is completed exactly once, either with a value or an error.
+// It is safe for concurrent use.
+//
+// Available since v0.11.0
+type Future[T any] struct {
+	done chan struct{}
+
+	mu        sync.Mutex
+	completed bool
+	cancelled bool
+	value     T
+	err       error
+	callbacks []func()
+	onCancel  []func()
+}
+
+// Struct Promise is the write side of a Future.
+//
+// Available since v0.11.0
+type Promise[T any] struct {
+	future *Future[T]
+}
+
+// Return new Promise with its Future not completed.
+//
+// Available since v0.11.0
+func NewPromise[T any]() *Promise[T] {
+	return &Promise[T]{future: newFuture[T]()}
+}
+
+// Return the Future of the Promise.
+//
+// Available since v0.11.0
+func (p *Promise[T]) Future() *Future[T] {
+	return p.future
+}
+
+// Complete the Future with value. Return false if it was already completed.
+//
+// Available since v0.11.0
+func (p *Promise[T]) Resolve(value T) bool {
+	return p.future.complete(value, nil)
+}
+
+// Complete the Future with err. Return false if it was already completed.
+//
+// Available since v0.11.0
+func (p *Promise[T]) Reject(err error) bool {
+	var zero T
+	return p.future.complete(zero, err)
+}
+
+// Complete the Future with value if err is nil, otherwise with err.
+// Return false if it was already completed.
+//
+// Available since v0.11.0
+func (p *Promise[T]) Complete(value T, err error) bool {
+	if err != nil {
+		return p.Reject(err)
+	}
+	return p.Resolve(value)
+}
+
+// Register fn to be called when the Future is cancelled, so the producer can stop its work.
+// fn is called immediately if the Future was already cancelled.
+//
+// Available since v0.11.0
+func (p *Promise[T]) OnCancel(fn func()) {
+	f := p.future
+	f.mu.Lock()
+	if !f.completed {
+		f.onCancel = append(f.onCancel, fn)
+		f.mu.Unlock()
+		return
+	}
+	cancelled := f.cancelled
+	f.mu.Unlock()
+	if cancelled {
+		fn()
+	}
+}
+
+// Return a Future completed with value.
+//
+// Available since v0.11.0
+func Resolved[T any](value T) *Future[T] {
+	f := newFuture[T]()
+	f.complete(value, nil)
+	return f
+}
+
+// Return a Future completed with err.
+//
+// Available since v0.11.0
+func Rejected[T any](err error) *Future[T] {
+	var zero T
+	f := newFuture[T]()
+	f.complete(zero, err)
+	return f
+}
+
+// Run fn in a new goroutine and return a Future of its result.
+// Cancelling the Future cancels the context passed to fn.
+// A panic in fn rejects the Future with an error wrapping ErrPanicked.
+//
+// Available since v0.11.0
+func Run[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	p := NewPromise[T]()
+	p.OnCancel(cancel)
+	go func() {
+		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				p.Reject(fmt.Errorf("%w: %v", ErrPanicked, r))
+			}
+		}()
+		p.Complete(fn(ctx))
+	}()
+	return p.Future()
+}
+
+// Wait until the Future is completed and return its outcome.
+// Return the error of ctx if it is done before that. The Future is not cancelled in this case.
+//
+// Available since v0.11.0
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Return a channel closed when the Future is completed.
+//
+// Available since v0.11.0
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Return the outcome of the Future without waiting, and whether it is completed.
+//
+// Available since v0.11.0
+func (f *Future[T]) Result() (T, bool, error) {
+	select {
+	case <-f.done:
+		return f.value, true, f.err
+	default:
+		var zero T
+		return zero, false, nil
+	}
+}
+
+// Complete the Future with context.Canceled then notify its producer.
+// Return false if it was already completed.
+//
+// Available since v0.11.0
+func (f *Future[T]) Cancel() bool {
+	var zero T
+	onCancel, ok := f.finish(zero, context.Canceled, true)
+	for _, fn := range onCancel {
+		fn()
+	}
+	return ok
+}
+
+// Return whether the Future was completed by Cancel.
+func (f *Future[T]) isCancelled() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cancelled
+}
+
+// Return new Future not completed.
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+// Store the outcome, signal waiters then run callbacks. Only the first outcome is kept.
+func (f *Future[T]) complete(value T, err error) bool {
+	_, ok := f.finish(value, err, false)
+	return ok
+}
+
+// Store the outcome, signal waiters then run callbacks.
+// Return the cancellation callbacks to run if the Future was cancelled by this call.
+func (f *Future[T]) finish(value T, err error, cancel bool) ([]func(), bool) {
+	f.mu.Lock()
+	if f.completed {
+		f.mu.Unlock()
+		return nil, false
+	}
+	f.completed = true
+	f.cancelled = cancel
+	f.value = value
+	f.err = err
+	callbacks, onCancel := f.callbacks, f.onCancel
+	f.callbacks, f.onCancel = nil, nil
+	close(f.done)
+	f.mu.Unlock()
+	for _, fn := range callbacks {
+		fn()
+	}
+	if !cancel {
+		onCancel = nil
+	}
+	return onCancel, true
+}
+
+// Register fn to be called once the Future is completed, immediately if it already is.
+// fn must not block.
+func (f *Future[T]) onComplete(fn func()) {
+	f.mu.Lock()
+	if !f.completed {
+		f.callbacks = append(f.callbacks, fn)
+		f.mu.Unlock()
+		return
+	}
+	f.mu.Unlock()
+	fn()
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromise_Resolve(t *testing.T) {
+	p := NewPromise[int]()
+	f := p.Future()
+
+	_, done, _ := f.Result()
+	assert.False(t, done)
+
+	go p.Resolve(42)
+	value, err := f.Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+
+	assert.False(t, p.Resolve(1))
+	assert.False(t, p.Reject(errors.New("late")))
+	value, done, err = f.Result()
+	assert.True(t, done)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+}
+
+func TestPromise_Reject(t *testing.T) {
+	boom := errors.New("boom")
+	p := NewPromise[string]()
+	assert.True(t, p.Complete("ignored", boom))
+
+	<-p.Future().Done()
+	value, err := p.Future().Await(context.Background())
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, "", value)
+}
+
+func TestFuture_AwaitContext(t *testing.T) {
+	p := NewPromise[int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := p.Future().Await(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, done, _ := p.Future().Result()
+	assert.False(t, done)
+}
+
+func TestFuture_Cancel(t *testing.T) {
+	p := NewPromise[int]()
+	cancelled := 0
+	p.OnCancel(func() { cancelled++ })
+
+	assert.True(t, p.Future().Cancel())
+	assert.False(t, p.Future().Cancel())
+	assert.False(t, p.Resolve(1))
+	_, err := p.Future().Await(context.Background())
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, cancelled)
+
+	p.OnCancel(func() { cancelled++ })
+	assert.Equal(t, 2, cancelled)
+
+	resolved := Resolved(1)
+	resolved.Cancel()
+	value, err := resolved.Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
+
+func TestRun(t *testing.T) {
+	f := Run(context.Background(), func(ctx context.Context) (int, error) {
+		return 7, nil
+	})
+	value, err := f.Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 7, value)
+
+	stopped := make(chan error, 1)
+	f = Run(context.Background(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return 0, ctx.Err()
+	})
+	f.Cancel()
+	assert.ErrorIs(t, <-stopped, context.Canceled)
+
+	f = Run(context.Background(), func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+	_, err = f.Await(context.Background())
+	assert.ErrorIs(t, err, ErrPanicked)
+}
+
+func TestFuture_Concurrency(t *testing.T) {
+	p := NewPromise[int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			p.Resolve(i)
+		}(i)
+		go func() {
+			defer wg.Done()
+			p.Future().Await(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	_, done, err := p.Future().Result()
+	assert.True(t, done)
+	assert.NoError(t, err)
+}
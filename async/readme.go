@@ -0,0 +1,13 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package async provides generic futures and promises for results that are computed
+asynchronously, with combinators to chain and join them.
+
+Available since v0.11.0
+*/
+package async
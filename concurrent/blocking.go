@@ -0,0 +1,163 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned when putting into a closed collection, or taking from a closed and empty one.
+//
+// Available since v0.11.0
+var ErrClosed = errors.New("collection closed")
+
+// ErrTimeout is returned when a blocking operation didn't complete in time.
+//
+// Available since v0.11.0
+var ErrTimeout = errors.New("operation timed out")
+
+// ErrInvalidCapacity is returned when a bounded collection is created with a non-positive capacity.
+//
+// Available since v0.11.0
+var ErrInvalidCapacity = errors.New("capacity must be positive")
+
+// blocking is a lock with a broadcast signal that can be awaited together with a context.
+type blocking struct {
+	mu     sync.Mutex
+	signal chan struct{}
+	closed bool
+}
+
+// Must be called with lock held. Wait until ready returns true, releasing the lock while waiting.
+// The lock is held again on return. Return the error of ctx if it is done before ready.
+func (b *blocking) wait(ctx context.Context, ready func() bool) error {
+	for !ready() {
+		if b.signal == nil {
+			b.signal = make(chan struct{})
+		}
+		signal := b.signal
+		b.mu.Unlock()
+		select {
+		case <-signal:
+			b.mu.Lock()
+		case <-ctx.Done():
+			b.mu.Lock()
+			if ready() {
+				return nil
+			}
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Must be called with lock held. Wake up all waiting routines.
+func (b *blocking) broadcast() {
+	if b.signal != nil {
+		close(b.signal)
+		b.signal = nil
+	}
+}
+
+// Must be called with lock held. Reject further puts and wake up all waiting routines.
+func (b *blocking) close() {
+	b.closed = true
+	b.broadcast()
+}
+
+// Run fn with a context expiring after timeout. Non-positive timeout waits forever.
+// Return ErrTimeout instead of context.DeadlineExceeded.
+func withTimeout(timeout time.Duration, fn func(ctx context.Context) error) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	err := fn(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return err
+}
+
+// ring is a growable circular buffer.
+type ring[T any] struct {
+	items []T
+	head  int
+	size  int
+}
+
+// Return number of items.
+func (r *ring[T]) len() int {
+	return r.size
+}
+
+// Append item at the back.
+func (r *ring[T]) pushBack(item T) {
+	r.grow()
+	r.items[(r.head+r.size)%len(r.items)] = item
+	r.size++
+}
+
+// Insert item at the front.
+func (r *ring[T]) pushFront(item T) {
+	r.grow()
+	r.head = (r.head - 1 + len(r.items)) % len(r.items)
+	r.items[r.head] = item
+	r.size++
+}
+
+// Remove and return the front item. Must not be called when empty.
+func (r *ring[T]) popFront() T {
+	var zero T
+	item := r.items[r.head]
+	r.items[r.head] = zero
+	r.head = (r.head + 1) % len(r.items)
+	r.size--
+	return item
+}
+
+// Remove and return the back item. Must not be called when empty.
+func (r *ring[T]) popBack() T {
+	var zero T
+	index := (r.head + r.size - 1) % len(r.items)
+	item := r.items[index]
+	r.items[index] = zero
+	r.size--
+	return item
+}
+
+// Return the front item. Must not be called when empty.
+func (r *ring[T]) front() T {
+	return r.items[r.head]
+}
+
+// Return the back item. Must not be called when empty.
+func (r *ring[T]) back() T {
+	return r.items[(r.head+r.size-1)%len(r.items)]
+}
+
+// Double the buffer if it is full.
+func (r *ring[T]) grow() {
+	if r.size < len(r.items) {
+		return
+	}
+	capacity := len(r.items) * 2
+	if capacity == 0 {
+		capacity = 8
+	}
+	items := make([]T, capacity)
+	for i := 0; i < r.size; i++ {
+		items[i] = r.items[(r.head+i)%len(r.items)]
+	}
+	r.items = items
+	r.head = 0
+}
@@ -0,0 +1,288 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"time"
+)
+
+// Struct BlockingDeque is a double-ended queue that blocks takers while it is empty
+// and putters while it is full.
+//
+// Available since v0.11.0
+type BlockingDeque[T any] struct {
+	blocking
+	items    ring[T]
+	capacity int
+}
+
+// Return new BlockingDeque holding at most capacity items.
+// Return ErrInvalidCapacity if capacity is not positive.
+//
+// Available since v0.11.0
+func NewBlockingDeque[T any](capacity int) (*BlockingDeque[T], error) {
+	if capacity <= 0 {
+		return nil, ErrInvalidCapacity
+	}
+	return &BlockingDeque[T]{capacity: capacity}, nil
+}
+
+// Return new BlockingDeque without capacity limit. Puts never wait.
+//
+// Available since v0.11.0
+func NewUnboundedBlockingDeque[T any]() *BlockingDeque[T] {
+	return &BlockingDeque[T]{}
+}
+
+// Insert item at the front, waiting for space until ctx is done.
+// Return ErrClosed if the deque is closed, or the error of ctx.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) PutFirst(ctx context.Context, item T) error {
+	return d.put(ctx, item, true)
+}
+
+// Append item at the back, waiting for space until ctx is done.
+// Return ErrClosed if the deque is closed, or the error of ctx.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) PutLast(ctx context.Context, item T) error {
+	return d.put(ctx, item, false)
+}
+
+// Insert item at the front, waiting for space at most timeout. Non-positive timeout waits forever.
+// Return ErrClosed if the deque is closed, or ErrTimeout.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) PutFirstTimeout(item T, timeout time.Duration) error {
+	return withTimeout(timeout, func(ctx context.Context) error {
+		return d.put(ctx, item, true)
+	})
+}
+
+// Append item at the back, waiting for space at most timeout. Non-positive timeout waits forever.
+// Return ErrClosed if the deque is closed, or ErrTimeout.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) PutLastTimeout(item T, timeout time.Duration) error {
+	return withTimeout(timeout, func(ctx context.Context) error {
+		return d.put(ctx, item, false)
+	})
+}
+
+// Insert item at the front without waiting. Return false if the deque is full or closed.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) OfferFirst(item T) bool {
+	return d.offer(item, true)
+}
+
+// Append item at the back without waiting. Return false if the deque is full or closed.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) OfferLast(item T) bool {
+	return d.offer(item, false)
+}
+
+// Remove and return the front item, waiting for one until ctx is done.
+// Return ErrClosed if the deque is closed and empty, or the error of ctx.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) TakeFirst(ctx context.Context) (T, error) {
+	return d.take(ctx, true)
+}
+
+// Remove and return the back item, waiting for one until ctx is done.
+// Return ErrClosed if the deque is closed and empty, or the error of ctx.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) TakeLast(ctx context.Context) (T, error) {
+	return d.take(ctx, false)
+}
+
+// Remove and return the front item, waiting for one at most timeout. Non-positive timeout waits forever.
+// Return ErrClosed if the deque is closed and empty, or ErrTimeout.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) TakeFirstTimeout(timeout time.Duration) (item T, err error) {
+	err = withTimeout(timeout, func(ctx context.Context) error {
+		item, err = d.take(ctx, true)
+		return err
+	})
+	return item, err
+}
+
+// Remove and return the back item, waiting for one at most timeout. Non-positive timeout waits forever.
+// Return ErrClosed if the deque is closed and empty, or ErrTimeout.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) TakeLastTimeout(timeout time.Duration) (item T, err error) {
+	err = withTimeout(timeout, func(ctx context.Context) error {
+		item, err = d.take(ctx, false)
+		return err
+	})
+	return item, err
+}
+
+// Remove and return the front item without waiting. Return false if the deque is empty.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) PollFirst() (T, bool) {
+	return d.poll(true)
+}
+
+// Remove and return the back item without waiting. Return false if the deque is empty.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) PollLast() (T, bool) {
+	return d.poll(false)
+}
+
+// Return the front item without removing it. Return false if the deque is empty.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) PeekFirst() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.items.len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.items.front(), true
+}
+
+// Return the back item without removing it. Return false if the deque is empty.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) PeekLast() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.items.len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.items.back(), true
+}
+
+// Return number of items.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.items.len()
+}
+
+// Return maximum number of items, 0 if unbounded.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) Cap() int {
+	return d.capacity
+}
+
+// Close the deque. Further puts fail with ErrClosed, while remaining items can still be taken.
+// Waiting routines are woken up.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.close()
+}
+
+// Return whether the deque is closed.
+//
+// Available since v0.11.0
+func (d *BlockingDeque[T]) Closed() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closed
+}
+
+// Return whether the deque is full. Must be called with lock held.
+func (d *BlockingDeque[T]) full() bool {
+	return d.capacity > 0 && d.items.len() >= d.capacity
+}
+
+// Add item at either end, waiting for space until ctx is done.
+func (d *BlockingDeque[T]) put(ctx context.Context, item T, front bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	err := d.wait(ctx, func() bool {
+		return d.closed || !d.full()
+	})
+	if err != nil {
+		return err
+	}
+	if d.closed {
+		return ErrClosed
+	}
+	d.push(item, front)
+	return nil
+}
+
+// Add item at either end without waiting.
+func (d *BlockingDeque[T]) offer(item T, front bool) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed || d.full() {
+		return false
+	}
+	d.push(item, front)
+	return true
+}
+
+// Add item at either end. Must be called with lock held.
+func (d *BlockingDeque[T]) push(item T, front bool) {
+	if front {
+		d.items.pushFront(item)
+	} else {
+		d.items.pushBack(item)
+	}
+	d.broadcast()
+}
+
+// Remove item from either end, waiting for one until ctx is done.
+func (d *BlockingDeque[T]) take(ctx context.Context, front bool) (T, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	err := d.wait(ctx, func() bool {
+		return d.closed || d.items.len() > 0
+	})
+	if err == nil && d.items.len() == 0 {
+		err = ErrClosed
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return d.pop(front), nil
+}
+
+// Remove item from either end without waiting.
+func (d *BlockingDeque[T]) poll(front bool) (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.items.len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.pop(front), true
+}
+
+// Remove item from either end. Must be called with lock held.
+func (d *BlockingDeque[T]) pop(front bool) T {
+	var item T
+	if front {
+		item = d.items.popFront()
+	} else {
+		item = d.items.popBack()
+	}
+	d.broadcast()
+	return item
+}
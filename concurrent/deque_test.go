@@ -0,0 +1,165 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockingDeque_Ends(t *testing.T) {
+	d := NewUnboundedBlockingDeque[int]()
+	ctx := context.Background()
+
+	assert.NoError(t, d.PutLast(ctx, 2))
+	assert.NoError(t, d.PutFirst(ctx, 1))
+	assert.True(t, d.OfferLast(3))
+	assert.True(t, d.OfferFirst(0))
+	assert.Equal(t, 4, d.Len())
+
+	first, _ := d.PeekFirst()
+	last, _ := d.PeekLast()
+	assert.Equal(t, 0, first)
+	assert.Equal(t, 3, last)
+
+	value, err := d.TakeLast(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, value)
+	value, err = d.TakeFirst(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, value)
+	value, _ = d.PollLast()
+	assert.Equal(t, 2, value)
+	value, _ = d.PollFirst()
+	assert.Equal(t, 1, value)
+
+	_, ok := d.PollFirst()
+	assert.False(t, ok)
+	_, ok = d.PeekLast()
+	assert.False(t, ok)
+}
+
+func TestBlockingDeque_Grow(t *testing.T) {
+	d := NewUnboundedBlockingDeque[int]()
+	for i := 0; i < 20; i++ {
+		d.OfferFirst(i)
+	}
+	for i := 0; i < 20; i++ {
+		value, _ := d.PollLast()
+		assert.Equal(t, i, value)
+	}
+}
+
+func TestBlockingDeque_Timeout(t *testing.T) {
+	d, _ := NewBlockingDeque[int](1)
+	assert.Equal(t, 1, d.Cap())
+
+	_, err := d.TakeFirstTimeout(10 * time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	assert.NoError(t, d.PutLastTimeout(1, 10*time.Millisecond))
+	assert.False(t, d.OfferFirst(2))
+	err = d.PutFirstTimeout(2, 10*time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = d.PutLast(ctx, 2)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	value, err := d.TakeLastTimeout(10 * time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
+
+func TestBlockingDeque_Close(t *testing.T) {
+	d := NewUnboundedBlockingDeque[int]()
+	d.OfferLast(1)
+
+	taken := make(chan error, 1)
+	empty := NewUnboundedBlockingDeque[int]()
+	go func() {
+		_, err := empty.TakeFirst(context.Background())
+		taken <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	empty.Close()
+	assert.ErrorIs(t, <-taken, ErrClosed)
+
+	d.Close()
+	assert.True(t, d.Closed())
+	assert.ErrorIs(t, d.PutFirst(context.Background(), 2), ErrClosed)
+	assert.False(t, d.OfferLast(2))
+
+	value, err := d.TakeFirst(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+	_, err = d.TakeLast(context.Background())
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestNewBlockingDeque_InvalidCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		d, err := NewBlockingDeque[int](capacity)
+		assert.Nil(t, d)
+		assert.ErrorIs(t, err, ErrInvalidCapacity)
+	}
+	assert.Equal(t, 0, NewUnboundedBlockingDeque[int]().Cap())
+}
+
+func TestBlockingDeque_Concurrency(t *testing.T) {
+	d, _ := NewBlockingDeque[int](4)
+	ctx := context.Background()
+
+	var producers sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		producers.Add(1)
+		go func(i int) {
+			defer producers.Done()
+			for j := 0; j < 100; j++ {
+				if j%2 == 0 {
+					d.PutFirst(ctx, 1)
+				} else {
+					d.PutLast(ctx, 1)
+				}
+			}
+		}(i)
+	}
+
+	sums := make(chan int, 5)
+	for i := 0; i < 5; i++ {
+		go func(i int) {
+			sum := 0
+			for {
+				var value int
+				var err error
+				if i%2 == 0 {
+					value, err = d.TakeFirst(ctx)
+				} else {
+					value, err = d.TakeLast(ctx)
+				}
+				if err != nil {
+					sums <- sum
+					return
+				}
+				sum += value
+			}
+		}(i)
+	}
+
+	producers.Wait()
+	d.Close()
+	total := 0
+	for i := 0; i < 5; i++ {
+		total += <-sums
+	}
+	assert.Equal(t, 1000, total)
+}
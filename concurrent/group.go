@@ -0,0 +1,105 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"sync"
+)
+
+// Struct Group runs tasks in goroutines with bounded concurrency and waits for them,
+// similar to errgroup. Only Workers, ErrorMode and Progress of PoolConfig apply to a Group.
+//
+// Available since v0.11.0
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	config PoolConfig
+	slots  chan struct{}
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	index int
+	errs  []error
+}
+
+// Return new Group and a context derived from ctx for its tasks.
+// In FailFast mode, the context is cancelled on the first error.
+// The context is always cancelled when Wait returns.
+// Zero config.Workers means no limit.
+//
+// Available since v0.11.0
+func NewGroup(ctx context.Context, config PoolConfig) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	g := &Group{
+		ctx:    ctx,
+		cancel: cancel,
+		config: config,
+	}
+	if config.Workers > 0 {
+		g.slots = make(chan struct{}, config.Workers)
+	}
+	return g, ctx
+}
+
+// Run task in a new goroutine, waiting for a free slot if the concurrency limit is reached.
+// Tasks are indexed in the order they are started.
+//
+// Available since v0.11.0
+func (g *Group) Go(task func(ctx context.Context) error) {
+	if g.slots != nil {
+		g.slots <- struct{}{}
+	}
+	g.mu.Lock()
+	index := g.index
+	g.index++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			if g.slots != nil {
+				<-g.slots
+			}
+			g.wg.Done()
+		}()
+		_, err := runJob(g.ctx, func(ctx context.Context, _ struct{}) (struct{}, error) {
+			return struct{}{}, task(ctx)
+		}, struct{}{})
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.config.Progress != nil {
+			g.config.Progress.Complete(1)
+		}
+		if err != nil {
+			g.errs = append(g.errs, &JobError{Index: index, Err: err})
+			if g.config.ErrorMode == FailFast {
+				g.cancel()
+			}
+		}
+	}()
+}
+
+// Wait for all tasks to finish.
+// In FailFast mode, return the first error as *JobError.
+// In CollectAll mode, return all errors as *MultiError of *JobError.
+//
+// Available since v0.11.0
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	if g.config.ErrorMode == FailFast {
+		return g.errs[0]
+	}
+	return &MultiError{Errors: append([]error{}, g.errs...)}
+}
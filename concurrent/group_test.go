@@ -0,0 +1,90 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestGroup_Success(t *testing.T) {
+	progress := diag.NewProgress(10)
+	g, _ := NewGroup(context.Background(), PoolConfig{Workers: 2, Progress: progress})
+
+	var running, peak, sum int64
+	for i := 1; i <= 10; i++ {
+		i := i
+		g.Go(func(ctx context.Context) error {
+			current := atomic.AddInt64(&running, 1)
+			if current > atomic.LoadInt64(&peak) {
+				atomic.StoreInt64(&peak, current)
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&sum, int64(i))
+			atomic.AddInt64(&running, -1)
+			return nil
+		})
+	}
+
+	assert.NoError(t, g.Wait())
+	assert.Equal(t, int64(55), sum)
+	assert.LessOrEqual(t, peak, int64(2))
+	assert.Equal(t, 100.0, progress.Percent())
+}
+
+func TestGroup_FailFast(t *testing.T) {
+	boom := errors.New("boom")
+	g, ctx := NewGroup(context.Background(), PoolConfig{})
+
+	g.Go(func(ctx context.Context) error {
+		return boom
+	})
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := g.Wait()
+	assert.ErrorIs(t, err, boom)
+	var jobErr *JobError
+	assert.ErrorAs(t, err, &jobErr)
+	assert.Equal(t, 0, jobErr.Index)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestGroup_CollectAll(t *testing.T) {
+	g, ctx := NewGroup(context.Background(), PoolConfig{Workers: 1, ErrorMode: CollectAll})
+
+	var completed int64
+	for i := 0; i < 4; i++ {
+		i := i
+		g.Go(func(ctx context.Context) error {
+			if i%2 == 0 {
+				return errors.New("even")
+			}
+			if i == 3 {
+				panic("three")
+			}
+			atomic.AddInt64(&completed, 1)
+			return ctx.Err()
+		})
+	}
+
+	err := g.Wait()
+	var multi *MultiError
+	assert.ErrorAs(t, err, &multi)
+	assert.Len(t, multi.Errors, 3)
+	assert.ErrorIs(t, err, ErrJobPanicked)
+	assert.Equal(t, int64(1), completed)
+	assert.Error(t, ctx.Err())
+}
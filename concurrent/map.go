@@ -0,0 +1,338 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// DefaultShardCount is the number of shards used by NewMap.
+//
+// Available since v0.11.0
+const DefaultShardCount = 32
+
+// Struct Map is a concurrent map split into shards, each guarded by its own lock,
+// so operations on keys of different shards don't block each other.
+// Operations on a single key, including Compute and Merge, are atomic.
+// Keys are distributed among shards by their values, including the fields of struct keys and
+// the elements of array keys. Pointer and channel keys are distributed by their addresses.
+// Use NewHashedMap to distribute keys by a custom hasher.
+//
+// Available since v0.11.0
+type Map[K comparable, V any] struct {
+	seed   maphash.Seed
+	hasher func(key K) uint64
+	shards []*mapShard[K, V]
+}
+
+// mapShard is a portion of a Map guarded by its own lock.
+type mapShard[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]V
+}
+
+// Return new empty Map with DefaultShardCount shards.
+//
+// Available since v0.11.0
+func NewMap[K comparable, V any]() *Map[K, V] {
+	return NewShardedMap[K, V](DefaultShardCount)
+}
+
+// Return new empty Map with specified number of shards. Shard count is at least 1.
+//
+// Available since v0.11.0
+func NewShardedMap[K comparable, V any](shardCount int) *Map[K, V] {
+	return NewHashedMap[K, V](shardCount, nil)
+}
+
+// Return new empty Map with specified number of shards, distributing keys by the result of hasher.
+// Equal keys must have the same hash. Nil hasher distributes keys by their values.
+// Shard count is at least 1.
+//
+// Available since v0.11.0
+func NewHashedMap[K comparable, V any](shardCount int, hasher func(key K) uint64) *Map[K, V] {
+	if shardCount < 1 {
+		shardCount = 1
+	}
+	m := &Map[K, V]{
+		seed:   maphash.MakeSeed(),
+		hasher: hasher,
+		shards: make([]*mapShard[K, V], shardCount),
+	}
+	if m.hasher == nil {
+		m.hasher = m.defaultHasher()
+	}
+	for i := range m.shards {
+		m.shards[i] = &mapShard[K, V]{items: make(map[K]V)}
+	}
+	return m
+}
+
+// Return the value stored for key and whether it was found.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) Load(key K) (V, bool) {
+	shard := m.shard(key)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	value, found := shard.items[key]
+	return value, found
+}
+
+// Set the value for key.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) Store(key K, value V) {
+	shard := m.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	shard.items[key] = value
+}
+
+// Return the existing value for key if present. Otherwise store and return value.
+// The returned bool is true if the value was loaded.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	shard := m.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if existing, found := shard.items[key]; found {
+		return existing, true
+	}
+	shard.items[key] = value
+	return value, false
+}
+
+// Delete the value for key and return it. The returned bool is true if the key was present.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) LoadAndDelete(key K) (V, bool) {
+	shard := m.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	value, found := shard.items[key]
+	delete(shard.items, key)
+	return value, found
+}
+
+// Delete the value for key.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) Delete(key K) {
+	shard := m.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	delete(shard.items, key)
+}
+
+// Compute a new value for key from its current value atomically.
+// fn receives the current value and whether it was found, then returns the new value
+// and whether the key should be kept. Returning false removes the key.
+// Return the new value and whether the key is present afterward.
+// fn must not access the Map, otherwise it may deadlock.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) Compute(key K, fn func(value V, found bool) (V, bool)) (V, bool) {
+	shard := m.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	value, found := shard.items[key]
+	value, keep := fn(value, found)
+	if !keep {
+		delete(shard.items, key)
+		var zero V
+		return zero, false
+	}
+	shard.items[key] = value
+	return value, true
+}
+
+// Store value for key if absent, otherwise store the result of fn applied on the current value and value.
+// Return the stored value.
+// fn must not access the Map, otherwise it may deadlock.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) Merge(key K, value V, fn func(current, value V) V) V {
+	merged, _ := m.Compute(key, func(current V, found bool) (V, bool) {
+		if !found {
+			return value, true
+		}
+		return fn(current, value), true
+	})
+	return merged
+}
+
+// Call fn for each key and value until fn returns false.
+// Each shard is locked for reading while its items are visited, so fn must not modify the Map.
+// Range doesn't reflect a consistent snapshot of the whole Map.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) Range(fn func(key K, value V) bool) {
+	for _, shard := range m.shards {
+		shard.mu.RLock()
+		for key, value := range shard.items {
+			if !fn(key, value) {
+				shard.mu.RUnlock()
+				return
+			}
+		}
+		shard.mu.RUnlock()
+	}
+}
+
+// Return all keys in unspecified order.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Return number of keys.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) Len() int {
+	count := 0
+	for _, shard := range m.shards {
+		shard.mu.RLock()
+		count += len(shard.items)
+		shard.mu.RUnlock()
+	}
+	return count
+}
+
+// Delete all keys.
+//
+// Available since v0.11.0
+func (m *Map[K, V]) Clear() {
+	for _, shard := range m.shards {
+		shard.mu.Lock()
+		shard.items = make(map[K]V)
+		shard.mu.Unlock()
+	}
+}
+
+// Return the shard holding key.
+func (m *Map[K, V]) shard(key K) *mapShard[K, V] {
+	if len(m.shards) == 1 {
+		return m.shards[0]
+	}
+	return m.shards[m.hasher(key)%uint64(len(m.shards))]
+}
+
+// Return hasher distributing keys by their values, chosen once from the key type.
+// Keys of string, boolean and numeric kinds are hashed directly,
+// other keys are hashed by walking their values.
+func (m *Map[K, V]) defaultHasher() func(key K) uint64 {
+	t := reflect.TypeOf((*K)(nil)).Elem()
+	if t == reflect.TypeOf("") {
+		return func(key K) uint64 {
+			return m.hashString(any(key).(string))
+		}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return func(key K) uint64 {
+			return m.hashString(reflect.ValueOf(key).String())
+		}
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return func(key K) uint64 {
+			return mix(scalarBits(reflect.ValueOf(key)))
+		}
+	}
+	return func(key K) uint64 {
+		var h maphash.Hash
+		h.SetSeed(m.seed)
+		writeValue(&h, reflect.ValueOf(key))
+		return h.Sum64()
+	}
+}
+
+// Return bits of boolean, numeric or pointer value v, so equal values have equal bits.
+func scalarBits(v reflect.Value) uint64 {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return 1
+		}
+		return 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f == 0 {
+			// 0 and -0 are equal keys.
+			f = 0
+		}
+		return math.Float64bits(f)
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		return uint64(v.Pointer())
+	}
+	return 0
+}
+
+// Write comparable value v to h, so equal values write the same bytes.
+func writeValue(h *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		// Nil interface.
+		h.WriteByte(0)
+	case reflect.String:
+		h.WriteString(v.String())
+		h.WriteByte(0)
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeValue(h, reflect.ValueOf(real(c)))
+		writeValue(h, reflect.ValueOf(imag(c)))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeValue(h, v.Field(i))
+		}
+	case reflect.Interface:
+		writeValue(h, v.Elem())
+	default:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], scalarBits(v))
+		h.Write(buf[:])
+	}
+}
+
+// Return seeded hash of the string.
+func (m *Map[K, V]) hashString(s string) uint64 {
+	var h maphash.Hash
+	h.SetSeed(m.seed)
+	h.WriteString(s)
+	return h.Sum64()
+}
+
+// Return x with its bits spread, so sequential integers don't land in sequential shards.
+func mix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
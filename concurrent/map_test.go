@@ -0,0 +1,219 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap_Basic(t *testing.T) {
+	m := NewMap[string, int]()
+
+	_, found := m.Load("a")
+	assert.False(t, found)
+
+	m.Store("a", 1)
+	value, found := m.Load("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+
+	value, loaded := m.LoadOrStore("a", 2)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, value)
+	value, loaded = m.LoadOrStore("b", 2)
+	assert.False(t, loaded)
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 2, m.Len())
+
+	value, found = m.LoadAndDelete("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+	_, found = m.LoadAndDelete("a")
+	assert.False(t, found)
+
+	m.Delete("b")
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestMap_Compute(t *testing.T) {
+	m := NewMap[string, int]()
+
+	value, present := m.Compute("a", func(value int, found bool) (int, bool) {
+		assert.False(t, found)
+		return 10, true
+	})
+	assert.True(t, present)
+	assert.Equal(t, 10, value)
+
+	value, present = m.Compute("a", func(value int, found bool) (int, bool) {
+		assert.True(t, found)
+		return value + 1, true
+	})
+	assert.True(t, present)
+	assert.Equal(t, 11, value)
+
+	_, present = m.Compute("a", func(value int, found bool) (int, bool) {
+		return 0, false
+	})
+	assert.False(t, present)
+	_, found := m.Load("a")
+	assert.False(t, found)
+}
+
+func TestMap_Merge(t *testing.T) {
+	m := NewMap[string, []string]()
+	join := func(current, value []string) []string {
+		return append(current, value...)
+	}
+
+	assert.Equal(t, []string{"x"}, m.Merge("a", []string{"x"}, join))
+	assert.Equal(t, []string{"x", "y"}, m.Merge("a", []string{"y"}, join))
+}
+
+func TestMap_KeyTypes(t *testing.T) {
+	type point struct{ X, Y int }
+	points := NewMap[point, string]()
+	points.Store(point{1, 2}, "a")
+	value, found := points.Load(point{1, 2})
+	assert.True(t, found)
+	assert.Equal(t, "a", value)
+
+	floats := NewMap[float64, int]()
+	floats.Store(0.0, 1)
+	zero := 0.0
+	value2, found := floats.Load(-zero)
+	assert.True(t, found)
+	assert.Equal(t, 1, value2)
+
+	type coordinate struct{ X, Y float64 }
+	coordinates := NewMap[coordinate, string]()
+	coordinates.Store(coordinate{0, 1}, "a")
+	value, found = coordinates.Load(coordinate{-zero, 1})
+	assert.True(t, found)
+	assert.Equal(t, "a", value)
+
+	type name string
+	names := NewMap[name, int]()
+	names.Store("a", 1)
+	value2, found = names.Load("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, value2)
+}
+
+func TestMap_CompositeKeys(t *testing.T) {
+	type point struct {
+		X, Y int
+		Name string
+	}
+	points := NewShardedMap[point, int](8)
+	arrays := NewShardedMap[[2]int, int](8)
+	pointers := NewShardedMap[*point, int](8)
+	targets := make([]*point, 64)
+	for i := 0; i < 64; i++ {
+		points.Store(point{i, -i, "p"}, i)
+		arrays.Store([2]int{i, i}, i)
+		targets[i] = &point{X: i}
+		pointers.Store(targets[i], i)
+	}
+	for _, shards := range [][]int{shardLens(points), shardLens(arrays), shardLens(pointers)} {
+		used := 0
+		for _, n := range shards {
+			if n > 0 {
+				used++
+			}
+		}
+		assert.Greater(t, used, 1)
+	}
+	value, found := points.Load(point{5, -5, "p"})
+	assert.True(t, found)
+	assert.Equal(t, 5, value)
+	value, found = pointers.Load(targets[7])
+	assert.True(t, found)
+	assert.Equal(t, 7, value)
+	_, found = pointers.Load(&point{X: 7})
+	assert.False(t, found)
+}
+
+func shardLens[K comparable, V any](m *Map[K, V]) []int {
+	lens := make([]int, len(m.shards))
+	for i, shard := range m.shards {
+		lens[i] = len(shard.items)
+	}
+	return lens
+}
+
+func TestMap_Hasher(t *testing.T) {
+	type point struct{ X, Y int }
+	m := NewHashedMap[point, int](4, func(key point) uint64 {
+		return uint64(key.X)
+	})
+	for i := 0; i < 8; i++ {
+		m.Store(point{i, i}, i)
+	}
+	assert.Equal(t, 8, m.Len())
+	for i, shard := range m.shards {
+		assert.Len(t, shard.items, 2, "shard %d", i)
+	}
+	value, found := m.Load(point{5, 5})
+	assert.True(t, found)
+	assert.Equal(t, 5, value)
+}
+
+func TestMap_RangeKeysClear(t *testing.T) {
+	m := NewShardedMap[int, int](4)
+	for i := 0; i < 100; i++ {
+		m.Store(i, i*i)
+	}
+
+	keys := m.Keys()
+	sort.Ints(keys)
+	assert.Len(t, keys, 100)
+	assert.Equal(t, 99, keys[99])
+
+	visited := 0
+	m.Range(func(key, value int) bool {
+		assert.Equal(t, key*key, value)
+		visited++
+		return visited < 10
+	})
+	assert.Equal(t, 10, visited)
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestMap_Concurrency(t *testing.T) {
+	m := NewMap[string, int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := strconv.Itoa(j % 10)
+				m.Merge(key, 1, func(current, value int) int {
+					return current + value
+				})
+				m.Load(key)
+				m.Store("worker"+strconv.Itoa(i), j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for j := 0; j < 10; j++ {
+		value, _ := m.Load(strconv.Itoa(j))
+		assert.Equal(t, 500, value)
+	}
+	assert.Equal(t, 60, m.Len())
+}
@@ -0,0 +1,286 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+// ErrJobPanicked is wrapped by the error of a job that panicked.
+//
+// Available since v0.11.0
+var ErrJobPanicked = errors.New("job panicked")
+
+// ErrorMode defines how a pool handles failed jobs.
+//
+// Available since v0.11.0
+type ErrorMode int8
+
+const (
+	// Cancel remaining jobs on the first error.
+	FailFast ErrorMode = iota
+
+	// Run all jobs and report all errors.
+	CollectAll
+)
+
+// Job processes an input and returns its result.
+//
+// Available since v0.11.0
+type Job[T, R any] func(ctx context.Context, input T) (R, error)
+
+// PoolConfig defines options of a worker pool.
+//
+// Available since v0.11.0
+type PoolConfig struct {
+	// Maximum number of jobs running at the same time. Default to number of CPUs.
+	Workers int
+	// Return results in the same order as inputs instead of completion order.
+	Ordered bool
+	// How failed jobs are handled.
+	ErrorMode ErrorMode
+	// Progress completed by one for each finished job. Its total is not changed.
+	Progress *diag.Progress
+}
+
+// Result is the outcome of a job with the index of its input.
+//
+// Available since v0.11.0
+type Result[R any] struct {
+	Index int
+	Value R
+	Err   error
+}
+
+// Struct JobError is the error of a job with the index of its input.
+//
+// Available since v0.11.0
+type JobError struct {
+	Index int
+	Err   error
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *JobError) Error() string {
+	return fmt.Sprintf("job %d: %v", e.Index, e.Err)
+}
+
+// Return the error of the job.
+//
+// Available since v0.11.0
+func (e *JobError) Unwrap() error {
+	return e.Err
+}
+
+// Struct MultiError holds the errors of all failed jobs.
+//
+// Available since v0.11.0
+type MultiError struct {
+	Errors []error
+}
+
+// Return the error message combining messages of all errors.
+//
+// Available since v0.11.0
+func (e *MultiError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Return whether any of the errors matches target.
+//
+// Available since v0.11.0
+func (e *MultiError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find the first error that matches target, and if so, set target to that error.
+//
+// Available since v0.11.0
+func (e *MultiError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run job over inputs with bounded concurrency and return the results.
+// Results are in completion order, or in input order if config.Ordered is set.
+// In FailFast mode, remaining jobs are cancelled on the first error, which is returned as *JobError.
+// In CollectAll mode, all jobs run and errors are returned as *MultiError of *JobError.
+// If ctx is done before all inputs are processed, the error of ctx is returned.
+//
+// Available since v0.11.0
+func Process[T, R any](ctx context.Context, inputs []T, job Job[T, R], config PoolConfig) ([]Result[R], error) {
+	in := make(chan T, len(inputs))
+	for _, input := range inputs {
+		in <- input
+	}
+	close(in)
+
+	results := make([]Result[R], 0, len(inputs))
+	var errs []error
+	for result := range Stream(ctx, in, job, config) {
+		results = append(results, result)
+		if result.Err != nil {
+			errs = append(errs, &JobError{Index: result.Index, Err: result.Err})
+		}
+	}
+	if len(errs) > 0 {
+		if config.ErrorMode == FailFast {
+			return results, errs[0]
+		}
+		return results, &MultiError{Errors: errs}
+	}
+	if len(results) < len(inputs) {
+		return results, ctx.Err()
+	}
+	return results, nil
+}
+
+// Run job over inputs received from a channel with bounded concurrency and emit the results.
+// Inputs are indexed in the order they are received. Results are emitted in completion order,
+// or in input order if config.Ordered is set. The returned channel is closed once inputs is closed
+// and all jobs finished.
+// In FailFast mode, remaining jobs are cancelled on the first error and the failing Result is the last one emitted.
+// If ctx is done, no more inputs are received and remaining results are discarded.
+// The caller must drain the returned channel or cancel ctx.
+//
+// Available since v0.11.0
+func Stream[T, R any](ctx context.Context, inputs <-chan T, job Job[T, R], config PoolConfig) <-chan Result[R] {
+	workers := config.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	jobCtx, cancel := context.WithCancel(ctx)
+
+	type task struct {
+		index int
+		input T
+	}
+	tasks := make(chan task)
+	go func() {
+		defer close(tasks)
+		for index := 0; ; index++ {
+			select {
+			case input, ok := <-inputs:
+				if !ok {
+					return
+				}
+				select {
+				case tasks <- task{index, input}:
+				case <-jobCtx.Done():
+					return
+				}
+			case <-jobCtx.Done():
+				return
+			}
+		}
+	}()
+
+	done := make(chan Result[R])
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range tasks {
+				value, err := runJob(jobCtx, job, task.input)
+				done <- Result[R]{Index: task.index, Value: value, Err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	out := make(chan Result[R])
+	go func() {
+		defer close(out)
+		defer cancel()
+		emit := func(result Result[R]) bool {
+			select {
+			case out <- result:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		failed := false
+		next := 0
+		pending := make(map[int]Result[R])
+		for result := range done {
+			if config.Progress != nil {
+				config.Progress.Complete(1)
+			}
+			if failed {
+				continue
+			}
+			if result.Err != nil && config.ErrorMode == FailFast {
+				failed = true
+				cancel()
+				if config.Ordered {
+					for ready, ok := pending[next]; ok; ready, ok = pending[next] {
+						delete(pending, next)
+						next++
+						if !emit(ready) {
+							break
+						}
+					}
+				}
+				emit(result)
+				continue
+			}
+			if !config.Ordered {
+				if !emit(result) {
+					failed = true
+				}
+				continue
+			}
+			pending[result.Index] = result
+			for ready, ok := pending[next]; ok; ready, ok = pending[next] {
+				delete(pending, next)
+				next++
+				if !emit(ready) {
+					failed = true
+					break
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Run job and convert a panic into an error wrapping ErrJobPanicked.
+func runJob[T, R any](ctx context.Context, job Job[T, R], input T) (value R, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrJobPanicked, r)
+		}
+	}()
+	return job(ctx, input)
+}
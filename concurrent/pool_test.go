@@ -0,0 +1,169 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func square(ctx context.Context, input int) (int, error) {
+	time.Sleep(time.Duration(input%3) * time.Millisecond)
+	return input * input, nil
+}
+
+func TestProcess_Ordered(t *testing.T) {
+	inputs := make([]int, 50)
+	for i := range inputs {
+		inputs[i] = i
+	}
+	progress := diag.NewProgress(float64(len(inputs)))
+
+	results, err := Process(context.Background(), inputs, square, PoolConfig{
+		Workers:  4,
+		Ordered:  true,
+		Progress: progress,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 50)
+	for i, result := range results {
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, i*i, result.Value)
+	}
+	assert.Equal(t, 100.0, progress.Percent())
+}
+
+func TestProcess_Unordered(t *testing.T) {
+	inputs := []int{5, 4, 3, 2, 1, 0}
+	results, err := Process(context.Background(), inputs, square, PoolConfig{Workers: 3})
+	assert.NoError(t, err)
+
+	values := make([]int, 0, len(results))
+	for _, result := range results {
+		assert.Equal(t, inputs[result.Index]*inputs[result.Index], result.Value)
+		values = append(values, result.Value)
+	}
+	sort.Ints(values)
+	assert.Equal(t, []int{0, 1, 4, 9, 16, 25}, values)
+}
+
+func TestProcess_BoundedConcurrency(t *testing.T) {
+	var running, peak int64
+	job := func(ctx context.Context, input int) (int, error) {
+		current := atomic.AddInt64(&running, 1)
+		for {
+			max := atomic.LoadInt64(&peak)
+			if current <= max || atomic.CompareAndSwapInt64(&peak, max, current) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		atomic.AddInt64(&running, -1)
+		return input, nil
+	}
+
+	_, err := Process(context.Background(), make([]int, 30), job, PoolConfig{Workers: 3})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, atomic.LoadInt64(&peak), int64(3))
+}
+
+func TestProcess_FailFast(t *testing.T) {
+	boom := errors.New("boom")
+	var started int64
+	job := func(ctx context.Context, input int) (int, error) {
+		atomic.AddInt64(&started, 1)
+		if input == 3 {
+			return 0, boom
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+		return input, nil
+	}
+	inputs := make([]int, 100)
+	for i := range inputs {
+		inputs[i] = i
+	}
+
+	results, err := Process(context.Background(), inputs, job, PoolConfig{Workers: 2, Ordered: true})
+	assert.ErrorIs(t, err, boom)
+	var jobErr *JobError
+	assert.ErrorAs(t, err, &jobErr)
+	assert.Equal(t, 3, jobErr.Index)
+
+	last := results[len(results)-1]
+	assert.Equal(t, 3, last.Index)
+	assert.ErrorIs(t, last.Err, boom)
+	assert.Less(t, atomic.LoadInt64(&started), int64(100))
+}
+
+func TestProcess_CollectAll(t *testing.T) {
+	odd := errors.New("odd")
+	job := func(ctx context.Context, input int) (int, error) {
+		if input%2 == 1 {
+			return 0, odd
+		}
+		if input == 4 {
+			panic("four")
+		}
+		return input, nil
+	}
+
+	results, err := Process(context.Background(), []int{0, 1, 2, 3, 4}, job, PoolConfig{
+		Ordered:   true,
+		ErrorMode: CollectAll,
+	})
+	assert.Len(t, results, 5)
+	var multi *MultiError
+	assert.ErrorAs(t, err, &multi)
+	assert.Len(t, multi.Errors, 3)
+	assert.ErrorIs(t, err, odd)
+	assert.ErrorIs(t, err, ErrJobPanicked)
+	assert.ErrorIs(t, results[4].Err, ErrJobPanicked)
+	assert.Equal(t, 2, results[2].Value)
+}
+
+func TestProcess_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := func(ctx context.Context, input int) (int, error) {
+		if input == 0 {
+			cancel()
+		}
+		<-ctx.Done()
+		return 0, nil
+	}
+
+	_, err := Process(ctx, make([]int, 10), job, PoolConfig{Workers: 1})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStream(t *testing.T) {
+	inputs := make(chan int)
+	go func() {
+		for i := 0; i < 20; i++ {
+			inputs <- i
+		}
+		close(inputs)
+	}()
+
+	next := 0
+	for result := range Stream(context.Background(), inputs, square, PoolConfig{Workers: 4, Ordered: true}) {
+		assert.Equal(t, next, result.Index)
+		assert.Equal(t, next*next, result.Value)
+		next++
+	}
+	assert.Equal(t, 20, next)
+}
@@ -0,0 +1,233 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNilLess is returned when a PriorityQueue is created without an ordering function.
+//
+// Available since v0.11.0
+var ErrNilLess = errors.New("less must not be nil")
+
+// Struct PriorityQueue is a bounded priority queue that blocks takers while it is empty
+// and putters while it is full. Items are taken in the order defined by less, smallest first.
+// Items of equal priority are taken in unspecified order.
+//
+// Available since v0.11.0
+type PriorityQueue[T any] struct {
+	blocking
+	items    []T
+	less     func(a, b T) bool
+	capacity int
+}
+
+// Return new PriorityQueue holding at most capacity items ordered by less.
+// Return ErrInvalidCapacity if capacity is not positive, or ErrNilLess if less is nil.
+//
+// Available since v0.11.0
+func NewPriorityQueue[T any](capacity int, less func(a, b T) bool) (*PriorityQueue[T], error) {
+	if capacity <= 0 {
+		return nil, ErrInvalidCapacity
+	}
+	if less == nil {
+		return nil, ErrNilLess
+	}
+	return &PriorityQueue[T]{
+		less:     less,
+		capacity: capacity,
+	}, nil
+}
+
+// Return new PriorityQueue without capacity limit ordered by less. Puts never wait.
+// Return ErrNilLess if less is nil.
+//
+// Available since v0.11.0
+func NewUnboundedPriorityQueue[T any](less func(a, b T) bool) (*PriorityQueue[T], error) {
+	if less == nil {
+		return nil, ErrNilLess
+	}
+	return &PriorityQueue[T]{less: less}, nil
+}
+
+// Insert item, waiting for space until ctx is done.
+// Return ErrClosed if the queue is closed, or the error of ctx.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) Put(ctx context.Context, item T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	err := q.wait(ctx, func() bool {
+		return q.closed || !q.full()
+	})
+	if err != nil {
+		return err
+	}
+	if q.closed {
+		return ErrClosed
+	}
+	q.push(item)
+	return nil
+}
+
+// Insert item, waiting for space at most timeout. Non-positive timeout waits forever.
+// Return ErrClosed if the queue is closed, or ErrTimeout.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) PutTimeout(item T, timeout time.Duration) error {
+	return withTimeout(timeout, func(ctx context.Context) error {
+		return q.Put(ctx, item)
+	})
+}
+
+// Insert item without waiting. Return false if the queue is full or closed.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) Offer(item T) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed || q.full() {
+		return false
+	}
+	q.push(item)
+	return true
+}
+
+// Remove and return the smallest item, waiting for one until ctx is done.
+// Return ErrClosed if the queue is closed and empty, or the error of ctx.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) Take(ctx context.Context) (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	err := q.wait(ctx, func() bool {
+		return q.closed || len(q.items) > 0
+	})
+	if err == nil && len(q.items) == 0 {
+		err = ErrClosed
+	}
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return q.pop(), nil
+}
+
+// Remove and return the smallest item, waiting for one at most timeout. Non-positive timeout waits forever.
+// Return ErrClosed if the queue is closed and empty, or ErrTimeout.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) TakeTimeout(timeout time.Duration) (item T, err error) {
+	err = withTimeout(timeout, func(ctx context.Context) error {
+		item, err = q.Take(ctx)
+		return err
+	})
+	return item, err
+}
+
+// Remove and return the smallest item without waiting. Return false if the queue is empty.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) Poll() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.pop(), true
+}
+
+// Return the smallest item without removing it. Return false if the queue is empty.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.items[0], true
+}
+
+// Return number of items.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Return maximum number of items, 0 if unbounded.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) Cap() int {
+	return q.capacity
+}
+
+// Close the queue. Further puts fail with ErrClosed, while remaining items can still be taken.
+// Waiting routines are woken up.
+//
+// Available since v0.11.0
+func (q *PriorityQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.close()
+}
+
+// Return whether the queue is full. Must be called with lock held.
+func (q *PriorityQueue[T]) full() bool {
+	return q.capacity > 0 && len(q.items) >= q.capacity
+}
+
+// Insert item into the heap. Must be called with lock held.
+func (q *PriorityQueue[T]) push(item T) {
+	q.items = append(q.items, item)
+	i := len(q.items) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i], q.items[parent]) {
+			break
+		}
+		q.items[i], q.items[parent] = q.items[parent], q.items[i]
+		i = parent
+	}
+	q.broadcast()
+}
+
+// Remove the smallest item from the heap. Must be called with lock held and heap not empty.
+func (q *PriorityQueue[T]) pop() T {
+	var zero T
+	item := q.items[0]
+	last := len(q.items) - 1
+	q.items[0] = q.items[last]
+	q.items[last] = zero
+	q.items = q.items[:last]
+	i := 0
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < last && q.less(q.items[left], q.items[smallest]) {
+			smallest = left
+		}
+		if right < last && q.less(q.items[right], q.items[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			break
+		}
+		q.items[i], q.items[smallest] = q.items[smallest], q.items[i]
+		i = smallest
+	}
+	q.broadcast()
+	return item
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityQueue_Order(t *testing.T) {
+	q, _ := NewUnboundedPriorityQueue(func(a, b int) bool { return a < b })
+	for _, value := range []int{5, 3, 8, 1, 9, 2, 7} {
+		assert.True(t, q.Offer(value))
+	}
+	assert.Equal(t, 7, q.Len())
+
+	smallest, _ := q.Peek()
+	assert.Equal(t, 1, smallest)
+
+	var values []int
+	for q.Len() > 0 {
+		value, err := q.Take(context.Background())
+		assert.NoError(t, err)
+		values = append(values, value)
+	}
+	assert.Equal(t, []int{1, 2, 3, 5, 7, 8, 9}, values)
+
+	_, ok := q.Poll()
+	assert.False(t, ok)
+	_, ok = q.Peek()
+	assert.False(t, ok)
+}
+
+func TestPriorityQueue_Bounded(t *testing.T) {
+	q, _ := NewPriorityQueue(2, func(a, b string) bool { return a > b })
+	assert.Equal(t, 2, q.Cap())
+	assert.True(t, q.Offer("a"))
+	assert.NoError(t, q.PutTimeout("c", time.Millisecond))
+	assert.False(t, q.Offer("b"))
+	assert.ErrorIs(t, q.PutTimeout("b", 10*time.Millisecond), ErrTimeout)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- q.Put(context.Background(), "b")
+	}()
+	value, err := q.TakeTimeout(time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "c", value)
+	assert.NoError(t, <-done)
+
+	value, _ = q.Poll()
+	assert.Equal(t, "b", value)
+
+	q.Close()
+	assert.False(t, q.Offer("d"))
+	value, err = q.Take(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "a", value)
+	_, err = q.Take(context.Background())
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestNewPriorityQueue_InvalidArguments(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	for _, capacity := range []int{0, -1} {
+		q, err := NewPriorityQueue(capacity, less)
+		assert.Nil(t, q)
+		assert.ErrorIs(t, err, ErrInvalidCapacity)
+	}
+	q, err := NewPriorityQueue[int](1, nil)
+	assert.Nil(t, q)
+	assert.ErrorIs(t, err, ErrNilLess)
+	q, err = NewUnboundedPriorityQueue[int](nil)
+	assert.Nil(t, q)
+	assert.ErrorIs(t, err, ErrNilLess)
+}
+
+func TestPriorityQueue_Concurrency(t *testing.T) {
+	q, _ := NewPriorityQueue(16, func(a, b int) bool { return a < b })
+
+	var producers sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		producers.Add(1)
+		go func(i int) {
+			defer producers.Done()
+			for j := 0; j < 50; j++ {
+				q.Put(context.Background(), i*50+j)
+			}
+		}(i)
+	}
+
+	results := make(chan []int, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			var values []int
+			for {
+				value, err := q.Take(context.Background())
+				if err != nil {
+					results <- values
+					return
+				}
+				values = append(values, value)
+			}
+		}()
+	}
+
+	producers.Wait()
+	q.Close()
+	var all []int
+	for i := 0; i < 3; i++ {
+		all = append(all, <-results...)
+	}
+	sort.Ints(all)
+	assert.Len(t, all, 500)
+	for i, value := range all {
+		assert.Equal(t, i, value)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"time"
+)
+
+// Struct BlockingQueue is a first-in-first-out queue that blocks takers while it is empty
+// and putters while it is full.
+//
+// Available since v0.11.0
+type BlockingQueue[T any] struct {
+	deque *BlockingDeque[T]
+}
+
+// Return new BlockingQueue holding at most capacity items.
+// Return ErrInvalidCapacity if capacity is not positive.
+//
+// Available since v0.11.0
+func NewBlockingQueue[T any](capacity int) (*BlockingQueue[T], error) {
+	deque, err := NewBlockingDeque[T](capacity)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockingQueue[T]{deque: deque}, nil
+}
+
+// Return new BlockingQueue without capacity limit. Puts never wait.
+//
+// Available since v0.11.0
+func NewUnboundedBlockingQueue[T any]() *BlockingQueue[T] {
+	return &BlockingQueue[T]{deque: NewUnboundedBlockingDeque[T]()}
+}
+
+// Append item, waiting for space until ctx is done.
+// Return ErrClosed if the queue is closed, or the error of ctx.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) Put(ctx context.Context, item T) error {
+	return q.deque.PutLast(ctx, item)
+}
+
+// Append item, waiting for space at most timeout. Non-positive timeout waits forever.
+// Return ErrClosed if the queue is closed, or ErrTimeout.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) PutTimeout(item T, timeout time.Duration) error {
+	return q.deque.PutLastTimeout(item, timeout)
+}
+
+// Append item without waiting. Return false if the queue is full or closed.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) Offer(item T) bool {
+	return q.deque.OfferLast(item)
+}
+
+// Remove and return the oldest item, waiting for one until ctx is done.
+// Return ErrClosed if the queue is closed and empty, or the error of ctx.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	return q.deque.TakeFirst(ctx)
+}
+
+// Remove and return the oldest item, waiting for one at most timeout. Non-positive timeout waits forever.
+// Return ErrClosed if the queue is closed and empty, or ErrTimeout.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) TakeTimeout(timeout time.Duration) (T, error) {
+	return q.deque.TakeFirstTimeout(timeout)
+}
+
+// Remove and return the oldest item without waiting. Return false if the queue is empty.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) Poll() (T, bool) {
+	return q.deque.PollFirst()
+}
+
+// Return the oldest item without removing it. Return false if the queue is empty.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) Peek() (T, bool) {
+	return q.deque.PeekFirst()
+}
+
+// Return number of items.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) Len() int {
+	return q.deque.Len()
+}
+
+// Return maximum number of items, 0 if unbounded.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) Cap() int {
+	return q.deque.Cap()
+}
+
+// Close the queue. Further puts fail with ErrClosed, while remaining items can still be taken.
+// Waiting routines are woken up.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) Close() {
+	q.deque.Close()
+}
+
+// Return whether the queue is closed.
+//
+// Available since v0.11.0
+func (q *BlockingQueue[T]) Closed() bool {
+	return q.deque.Closed()
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockingQueue_Order(t *testing.T) {
+	q := NewUnboundedBlockingQueue[string]()
+	ctx := context.Background()
+
+	assert.NoError(t, q.Put(ctx, "a"))
+	assert.True(t, q.Offer("b"))
+	assert.NoError(t, q.PutTimeout("c", time.Millisecond))
+	assert.Equal(t, 3, q.Len())
+	assert.Equal(t, 0, q.Cap())
+
+	head, _ := q.Peek()
+	assert.Equal(t, "a", head)
+	value, err := q.Take(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", value)
+	value, _ = q.Poll()
+	assert.Equal(t, "b", value)
+	value, err = q.TakeTimeout(time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, "c", value)
+
+	_, err = q.TakeTimeout(10 * time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestBlockingQueue_BlocksWhenFull(t *testing.T) {
+	q, _ := NewBlockingQueue[int](1)
+	q.Offer(1)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- q.Put(context.Background(), 2)
+	}()
+	select {
+	case <-done:
+		t.Fatal("Put should block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	value, _ := q.Poll()
+	assert.Equal(t, 1, value)
+	assert.NoError(t, <-done)
+	value, _ = q.Poll()
+	assert.Equal(t, 2, value)
+
+	q.Close()
+	assert.True(t, q.Closed())
+	assert.ErrorIs(t, q.Put(context.Background(), 3), ErrClosed)
+}
+
+func TestNewBlockingQueue_InvalidCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		q, err := NewBlockingQueue[int](capacity)
+		assert.Nil(t, q)
+		assert.ErrorIs(t, err, ErrInvalidCapacity)
+	}
+}
+
+func TestBlockingQueue_Concurrency(t *testing.T) {
+	q, _ := NewBlockingQueue[int](8)
+
+	var producers sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		producers.Add(1)
+		go func(i int) {
+			defer producers.Done()
+			for j := 0; j < 100; j++ {
+				q.PutTimeout(i*100+j, 0)
+			}
+		}(i)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	var consumers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		consumers.Add(1)
+		go func() {
+			defer consumers.Done()
+			for {
+				value, err := q.Take(context.Background())
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				seen[value] = true
+				mu.Unlock()
+			}
+		}()
+	}
+
+	producers.Wait()
+	q.Close()
+	consumers.Wait()
+	assert.Len(t, seen, 800)
+}
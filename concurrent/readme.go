@@ -0,0 +1,14 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package concurrent provides generic collections that are safe for concurrent use
+by multiple goroutines, including blocking queues with timeouts, and worker pools
+running jobs with bounded concurrency.
+
+Available since v0.11.0
+*/
+package concurrent
@@ -0,0 +1,79 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+// Struct Set is a concurrent set backed by a sharded Map.
+//
+// Available since v0.11.0
+type Set[T comparable] struct {
+	items *Map[T, struct{}]
+}
+
+// Return new Set containing items.
+//
+// Available since v0.11.0
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{items: NewMap[T, struct{}]()}
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
+// Add item to the set. Return false if it was already present.
+//
+// Available since v0.11.0
+func (s *Set[T]) Add(item T) bool {
+	_, loaded := s.items.LoadOrStore(item, struct{}{})
+	return !loaded
+}
+
+// Remove item from the set. Return false if it wasn't present.
+//
+// Available since v0.11.0
+func (s *Set[T]) Remove(item T) bool {
+	_, found := s.items.LoadAndDelete(item)
+	return found
+}
+
+// Return whether item is present.
+//
+// Available since v0.11.0
+func (s *Set[T]) Contains(item T) bool {
+	_, found := s.items.Load(item)
+	return found
+}
+
+// Call fn for each item until fn returns false. fn must not modify the set.
+//
+// Available since v0.11.0
+func (s *Set[T]) Range(fn func(item T) bool) {
+	s.items.Range(func(item T, _ struct{}) bool {
+		return fn(item)
+	})
+}
+
+// Return all items in unspecified order.
+//
+// Available since v0.11.0
+func (s *Set[T]) Items() []T {
+	return s.items.Keys()
+}
+
+// Return number of items.
+//
+// Available since v0.11.0
+func (s *Set[T]) Len() int {
+	return s.items.Len()
+}
+
+// Remove all items.
+//
+// Available since v0.11.0
+func (s *Set[T]) Clear() {
+	s.items.Clear()
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package concurrent
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet(1, 2, 2, 3)
+	assert.Equal(t, 3, s.Len())
+	assert.True(t, s.Contains(2))
+	assert.False(t, s.Contains(4))
+
+	assert.True(t, s.Add(4))
+	assert.False(t, s.Add(4))
+	assert.True(t, s.Remove(1))
+	assert.False(t, s.Remove(1))
+
+	items := s.Items()
+	sort.Ints(items)
+	assert.Equal(t, []int{2, 3, 4}, items)
+
+	s.Clear()
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestSet_Concurrency(t *testing.T) {
+	s := NewSet[int]()
+
+	var wg sync.WaitGroup
+	added := make(chan bool, 1000)
+	for i := 0; i < 1000; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			added <- s.Add(i % 100)
+			s.Contains(i)
+		}(i)
+	}
+	wg.Wait()
+	close(added)
+
+	count := 0
+	for ok := range added {
+		if ok {
+			count++
+		}
+	}
+	assert.Equal(t, 100, count)
+	assert.Equal(t, 100, s.Len())
+}
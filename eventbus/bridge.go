@@ -0,0 +1,58 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package eventbus
+
+import (
+	"context"
+
+	"github.com/tforce-io/tf-golib/multiplex"
+)
+
+// Parameter key holding the event of a bridged message.
+//
+// Available since v0.11.0
+const EventKey = "event"
+
+// Forward events of type E published on bus to serviceID via the router of controller, as messages with command.
+// Params of the messages are built by toParams, or hold the event under EventKey if toParams is nil.
+// Forwarding errors, such as multiplex.NoSuchServiceError, are reported as handler errors.
+// Unsubscribe the returned Subscription to stop bridging.
+//
+// Available since v0.11.0
+func Bridge[E any](bus *Bus, controller *multiplex.ServiceController, serviceID, command string, toParams func(event E) multiplex.ExecParams) *Subscription {
+	return Subscribe(bus, func(ctx context.Context, event E) error {
+		return controller.Router().ForwardE(serviceID, command, bridgeParams(event, toParams))
+	})
+}
+
+// Publish events of type E published on bus to all services subscribed to topic on controller, as messages with command.
+// Params of the messages are built by toParams, or hold the event under EventKey if toParams is nil.
+// Unsubscribe the returned Subscription to stop bridging.
+//
+// Available since v0.11.0
+func BridgeTopic[E any](bus *Bus, controller *multiplex.ServiceController, topic, command string, toParams func(event E) multiplex.ExecParams) *Subscription {
+	return Subscribe(bus, func(ctx context.Context, event E) error {
+		_, err := controller.Router().Publish(topic, command, bridgeParams(event, toParams))
+		return err
+	})
+}
+
+// Return the event of type E held by a bridged message under EventKey, and whether it was found.
+//
+// Available since v0.11.0
+func EventParam[E any](msg *multiplex.ServiceMessage) (E, bool) {
+	event, ok := msg.GetParam(EventKey, nil).(E)
+	return event, ok
+}
+
+// Return params of the bridged message for event.
+func bridgeParams[E any](event E, toParams func(event E) multiplex.ExecParams) multiplex.ExecParams {
+	if toParams != nil {
+		return toParams(event)
+	}
+	return multiplex.ExecParams{EventKey: event}
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package eventbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+	"github.com/tforce-io/tf-golib/multiplex"
+	"github.com/tforce-io/tf-golib/multiplex/multiplextest"
+)
+
+type orderPlaced struct {
+	ID    string
+	Total int
+}
+
+type auditService struct {
+	multiplex.ServiceCore
+	received chan orderPlaced
+}
+
+func newAuditService(logger diag.Logger) *auditService {
+	svc := &auditService{received: make(chan orderPlaced, 10)}
+	svc.InitServiceCore("Audit", logger, svc.coreProcessHook)
+	return svc
+}
+
+func (s *auditService) coreProcessHook(workerID uint64, msg *multiplex.ServiceMessage) *multiplex.HookState {
+	if msg.Command != "audit" {
+		return &multiplex.HookState{Handled: false}
+	}
+	if event, ok := EventParam[orderPlaced](msg); ok {
+		s.received <- event
+	}
+	return &multiplex.HookState{Handled: true}
+}
+
+func TestBridge(t *testing.T) {
+	controller := multiplextest.NewSyncController(t)
+	bus := New(controller.Logger)
+	Bridge[orderPlaced](bus, controller.ServiceController, "Orders", "placed", nil)
+	sub := Bridge(bus, controller.ServiceController, "Billing", "charge", func(event orderPlaced) multiplex.ExecParams {
+		return multiplex.ExecParams{"order_id": event.ID, "amount": event.Total}
+	})
+
+	assert.NoError(t, bus.Publish(context.Background(), orderPlaced{ID: "o1", Total: 30}))
+	orders := controller.DispatchesTo("Orders")
+	assert.Len(t, orders, 1)
+	assert.Equal(t, "placed", orders[0].Command)
+	assert.Equal(t, orderPlaced{ID: "o1", Total: 30}, orders[0].Params[EventKey])
+	controller.AssertDispatched("Billing", "charge", multiplex.ExecParams{"order_id": "o1", "amount": 30})
+
+	sub.Unsubscribe()
+	bus.Publish(context.Background(), orderPlaced{ID: "o2"})
+	controller.AssertDispatchCount("Billing", "charge", 1)
+	controller.AssertDispatchCount("Orders", "placed", 2)
+}
+
+func TestBridgeTopic(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := multiplex.NewServiceController(logger)
+	audit := newAuditService(logger)
+	audit.SetRouter(controller)
+	audit.SetWorker(1)
+	controller.Register(audit)
+	controller.Run(false)
+	assert.NoError(t, audit.Subscribe("orders.*"))
+
+	bus := New(logger)
+	BridgeTopic[orderPlaced](bus, controller, "orders.placed", "audit", nil)
+	assert.NoError(t, bus.Publish(context.Background(), orderPlaced{ID: "o1", Total: 5}))
+
+	select {
+	case event := <-audit.received:
+		assert.Equal(t, "o1", event.ID)
+	case <-time.After(time.Second):
+		t.Fatal("event not received by service")
+	}
+}
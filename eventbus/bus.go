@@ -0,0 +1,181 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package eventbus
+
+import (
+	"context"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/tforce-io/tf-golib/concurrent"
+	"github.com/tforce-io/tf-golib/diag"
+	"github.com/tforce-io/tf-golib/multiplex"
+)
+
+// Handler handles an event of type E.
+//
+// Available since v0.11.0
+type Handler[E any] func(ctx context.Context, event E) error
+
+// Struct Bus dispatches events to handlers subscribed to their type. It is safe for concurrent use.
+//
+// Available since v0.11.0
+type Bus struct {
+	logger diag.Logger
+
+	mu     sync.RWMutex
+	subs   map[reflect.Type][]*Subscription
+	lastID uint64
+
+	inflight sync.WaitGroup
+}
+
+// Struct Subscription is the registration of a handler on a Bus.
+//
+// Available since v0.11.0
+type Subscription struct {
+	bus       *Bus
+	id        uint64
+	eventType reflect.Type
+	handle    func(ctx context.Context, event interface{}) error
+}
+
+// Return new empty Bus. Errors of asynchronous handlers are logged to logger.
+//
+// Available since v0.11.0
+func New(logger diag.Logger) *Bus {
+	return &Bus{
+		logger: logger,
+		subs:   make(map[reflect.Type][]*Subscription),
+	}
+}
+
+// Subscribe handler to events of type E.
+// If E is an interface type, handler receives all events implementing it.
+// Handlers of an event are called in the order they subscribed.
+//
+// Available since v0.11.0
+func Subscribe[E any](bus *Bus, handler Handler[E]) *Subscription {
+	sub := &Subscription{
+		bus:       bus,
+		eventType: reflect.TypeOf((*E)(nil)).Elem(),
+		handle: func(ctx context.Context, event interface{}) error {
+			return handler(ctx, event.(E))
+		},
+	}
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.lastID++
+	sub.id = bus.lastID
+	bus.subs[sub.eventType] = append(bus.subs[sub.eventType], sub)
+	return sub
+}
+
+// Remove the subscription. Events already being dispatched may still reach the handler.
+//
+// Available since v0.11.0
+func (s *Subscription) Unsubscribe() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	subs := s.bus.subs[s.eventType]
+	for i, sub := range subs {
+		if sub == s {
+			subs = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(subs) == 0 {
+		delete(s.bus.subs, s.eventType)
+	} else {
+		s.bus.subs[s.eventType] = subs
+	}
+}
+
+// Dispatch event to its handlers one by one in the calling routine, and wait for them to complete.
+// A panicking handler doesn't prevent other handlers from running, its panic is returned as multiplex.PanicError.
+// Return nil if all handlers succeeded, otherwise *concurrent.MultiError of their errors.
+//
+// Available since v0.11.0
+func (b *Bus) Publish(ctx context.Context, event interface{}) error {
+	var errs []error
+	for _, sub := range b.handlers(event) {
+		if err := sub.call(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &concurrent.MultiError{Errors: errs}
+	}
+	return nil
+}
+
+// Dispatch event to each of its handlers in a new routine and return immediately
+// with the number of handlers. Errors of handlers are logged.
+//
+// Available since v0.11.0
+func (b *Bus) PublishAsync(ctx context.Context, event interface{}) int {
+	subs := b.handlers(event)
+	for _, sub := range subs {
+		b.inflight.Add(1)
+		go func(sub *Subscription) {
+			defer b.inflight.Done()
+			if err := sub.call(ctx, event); err != nil && b.logger != nil {
+				b.logger.Errorf(err, "EventBus: Handler of %T failed.", event)
+			}
+		}(sub)
+	}
+	return len(subs)
+}
+
+// Wait for handlers dispatched by PublishAsync to complete.
+//
+// Available since v0.11.0
+func (b *Bus) Wait() {
+	b.inflight.Wait()
+}
+
+// Return whether any subscription receives event.
+//
+// Available since v0.11.0
+func (b *Bus) HasSubscribers(event interface{}) bool {
+	return len(b.handlers(event)) > 0
+}
+
+// Return subscriptions receiving event, ordered by subscription.
+func (b *Bus) handlers(event interface{}) []*Subscription {
+	if event == nil {
+		return nil
+	}
+	eventType := reflect.TypeOf(event)
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var subs []*Subscription
+	for subType, typeSubs := range b.subs {
+		if subType == eventType || (subType.Kind() == reflect.Interface && eventType.Implements(subType)) {
+			subs = append(subs, typeSubs...)
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].id < subs[j].id
+	})
+	return subs
+}
+
+// Call the handler, converting a panic into multiplex.PanicError.
+func (s *Subscription) call(ctx context.Context, event interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &multiplex.PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+	return s.handle(ctx, event)
+}
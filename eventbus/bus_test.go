@@ -0,0 +1,141 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/concurrent"
+	"github.com/tforce-io/tf-golib/diag"
+	"github.com/tforce-io/tf-golib/multiplex"
+)
+
+type userCreated struct {
+	Name string
+}
+
+type userDeleted struct {
+	Name string
+}
+
+func (e userCreated) String() string {
+	return "created " + e.Name
+}
+
+func TestBus_Publish(t *testing.T) {
+	bus := New(diag.NewDebugLogger(10))
+	var received []string
+	Subscribe(bus, func(ctx context.Context, event userCreated) error {
+		received = append(received, "first "+event.Name)
+		return nil
+	})
+	Subscribe(bus, func(ctx context.Context, event *userCreated) error {
+		received = append(received, "pointer "+event.Name)
+		return nil
+	})
+	Subscribe(bus, func(ctx context.Context, event fmt.Stringer) error {
+		received = append(received, "stringer "+event.String())
+		return nil
+	})
+	Subscribe(bus, func(ctx context.Context, event userCreated) error {
+		received = append(received, "second "+event.Name)
+		return nil
+	})
+
+	assert.NoError(t, bus.Publish(context.Background(), userCreated{Name: "alice"}))
+	assert.Equal(t, []string{"first alice", "stringer created alice", "second alice"}, received)
+
+	received = nil
+	assert.NoError(t, bus.Publish(context.Background(), userDeleted{Name: "bob"}))
+	assert.Empty(t, received)
+	assert.False(t, bus.HasSubscribers(userDeleted{}))
+	assert.True(t, bus.HasSubscribers(&userCreated{}))
+	assert.NoError(t, bus.Publish(context.Background(), nil))
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := New(nil)
+	count := 0
+	sub := Subscribe(bus, func(ctx context.Context, event int) error {
+		count++
+		return nil
+	})
+	other := Subscribe(bus, func(ctx context.Context, event int) error {
+		count += 10
+		return nil
+	})
+
+	bus.Publish(context.Background(), 1)
+	sub.Unsubscribe()
+	bus.Publish(context.Background(), 1)
+	assert.Equal(t, 21, count)
+
+	other.Unsubscribe()
+	assert.False(t, bus.HasSubscribers(1))
+}
+
+func TestBus_PanicIsolation(t *testing.T) {
+	bus := New(nil)
+	boom := errors.New("boom")
+	called := false
+	Subscribe(bus, func(ctx context.Context, event string) error {
+		panic("handler crashed")
+	})
+	Subscribe(bus, func(ctx context.Context, event string) error {
+		return boom
+	})
+	Subscribe(bus, func(ctx context.Context, event string) error {
+		called = true
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), "event")
+	assert.True(t, called, "handlers after a panicking one must run")
+	var multi *concurrent.MultiError
+	assert.ErrorAs(t, err, &multi)
+	assert.Len(t, multi.Errors, 2)
+	var panicErr *multiplex.PanicError
+	assert.ErrorAs(t, err, &panicErr)
+	assert.Equal(t, "handler crashed", panicErr.Value)
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestBus_PublishAsync(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	bus := New(logger)
+	var total int64
+	for i := 0; i < 3; i++ {
+		Subscribe(bus, func(ctx context.Context, event int) error {
+			atomic.AddInt64(&total, int64(event))
+			return nil
+		})
+	}
+	Subscribe(bus, func(ctx context.Context, event int) error {
+		panic("async crash")
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, 4, bus.PublishAsync(context.Background(), 2))
+		}()
+	}
+	wg.Wait()
+	bus.Wait()
+
+	assert.Equal(t, int64(60), atomic.LoadInt64(&total))
+	assert.Len(t, logger.AllMessages(), 10)
+	assert.Contains(t, logger.LastMessage(), "EventBus: Handler of int failed.")
+}
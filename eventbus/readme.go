@@ -0,0 +1,13 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package eventbus provides an in-process event bus where handlers subscribe to events by their Go type.
+Events can be bridged into a multiplex.ServiceController to reach existing services.
+
+Available since v0.11.0
+*/
+package eventbus
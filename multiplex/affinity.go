@@ -0,0 +1,289 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Parameter key holding the affinity key of a message.
+//
+// Available since v0.11.0
+const AffinityKey = "affinity_key"
+
+// ErrWorkersStarted is returned when an option can only be changed before Process routines are started.
+//
+// Available since v0.11.0
+var ErrWorkersStarted = errors.New("workers already started")
+
+// AffinityFunc returns the affinity key of a message.
+// Messages with the same key are processed sequentially by the same Process routine.
+// Messages with empty key are distributed to Process routines in round-robin order.
+//
+// Available since v0.11.0
+type AffinityFunc func(msg *ServiceMessage) string
+
+// Return an AffinityFunc using the parameter with specified key as affinity key.
+//
+// Available since v0.11.0
+func AffinityParam(key string) AffinityFunc {
+	return func(msg *ServiceMessage) string {
+		value := msg.GetParam(key, nil)
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprint(value)
+	}
+}
+
+// Route messages to Process routines by their affinity key, so messages with the same key
+// are processed in order while messages with different keys are processed in parallel.
+// If keyFunc is nil, the AffinityKey parameter is used.
+// When the number of Process routines changes, routing is paused until all routed messages are
+// processed, then keys are redistributed to the new set of Process routines.
+// Affinity must be set before starting Process routines, otherwise ErrWorkersStarted is returned.
+// Calling it again only replaces keyFunc.
+//
+// Available since v0.11.0
+func (s ServiceCore) SetAffinity(keyFunc AffinityFunc) error {
+	if keyFunc == nil {
+		keyFunc = AffinityParam(AffinityKey)
+	}
+	s.i.workerMu.Lock()
+	defer s.i.workerMu.Unlock()
+	if s.i.affinity != nil {
+		s.i.affinity.mu.Lock()
+		s.i.affinity.keyFunc = keyFunc
+		s.i.affinity.mu.Unlock()
+		return nil
+	}
+	if s.i.WorkerCount > 0 || s.i.workers() > 0 {
+		return ErrWorkersStarted
+	}
+	s.i.affinity = newAffinity(keyFunc, s.i.MainChan)
+	return nil
+}
+
+// affinity routes messages from MainChan to one queue per Process routine.
+// The dispatch routine runs while Process routines are attached, it stops after routing the exit request
+// of the last one and starts again when a Process routine is attached.
+type affinity struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	main chan *ServiceMessage
+
+	keyFunc AffinityFunc
+	// Whether the dispatch routine is running.
+	running bool
+	// Process routines currently attached, by worker ID.
+	members map[uint64]*affinityShard
+	// Process routines receiving messages, ordered by worker ID.
+	shards []*affinityShard
+	// Whether members changed since shards was built.
+	dirty bool
+	// Number of messages routed but not yet processed.
+	pending int
+	// Messages of crashed Process routines to be routed again before reading MainChan.
+	requeue []*ServiceMessage
+	wake    chan struct{}
+	next    int
+}
+
+// affinityShard is the queue of a Process routine.
+type affinityShard struct {
+	workerID uint64
+	queue    []*ServiceMessage
+	pending  int
+	retired  bool
+}
+
+// Return new affinity routing messages from main, with no Process routine attached.
+func newAffinity(keyFunc AffinityFunc, main chan *ServiceMessage) *affinity {
+	a := &affinity{
+		main:    main,
+		keyFunc: keyFunc,
+		members: make(map[uint64]*affinityShard),
+		wake:    make(chan struct{}, 1),
+	}
+	a.cond = sync.NewCond(&a.mu)
+	return a
+}
+
+// Route messages from main to the queues of Process routines until the last one is retired.
+func (a *affinity) dispatch() {
+	for {
+		a.mu.Lock()
+		var msg *ServiceMessage
+		if len(a.requeue) > 0 {
+			msg = a.requeue[0]
+			a.requeue = a.requeue[1:]
+		}
+		a.mu.Unlock()
+		if msg == nil {
+			select {
+			case msg = <-a.main:
+			case <-a.wake:
+				continue
+			}
+		}
+		if !a.route(msg) {
+			return
+		}
+	}
+}
+
+// Append the message to the queue of a Process routine.
+// An exit message retires the Process routine receiving it.
+// Return false if the dispatch routine stopped because no Process routine is left.
+func (a *affinity) route(msg *ServiceMessage) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		if a.dirty && a.pending == 0 {
+			a.reshard()
+		}
+		if !a.dirty && len(a.shards) > 0 && a.pending < MainChainCapacity {
+			break
+		}
+		a.cond.Wait()
+	}
+	var shard *affinityShard
+	if msg.Command == "exit" {
+		shard = a.shards[len(a.shards)-1]
+		shard.retired = true
+		delete(a.members, shard.workerID)
+		a.dirty = true
+	} else if key := a.keyFunc(msg); key != "" {
+		hash := fnv.New32a()
+		hash.Write([]byte(key))
+		shard = a.shards[hash.Sum32()%uint32(len(a.shards))]
+	} else {
+		shard = a.shards[a.next%len(a.shards)]
+		a.next++
+	}
+	shard.queue = append(shard.queue, msg)
+	shard.pending++
+	a.pending++
+	a.cond.Broadcast()
+	if msg.Command == "exit" && len(a.members) == 0 && len(a.requeue) == 0 {
+		a.running = false
+		return false
+	}
+	return true
+}
+
+// Rebuild the list of Process routines receiving messages. Must be called with lock held.
+func (a *affinity) reshard() {
+	a.shards = make([]*affinityShard, 0, len(a.members))
+	for _, shard := range a.members {
+		a.shards = append(a.shards, shard)
+	}
+	sort.Slice(a.shards, func(i, j int) bool {
+		return a.shards[i].workerID < a.shards[j].workerID
+	})
+	a.dirty = false
+}
+
+// Create the queue of a Process routine and start the dispatch routine if needed.
+func (a *affinity) attach(workerID uint64) *affinityShard {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	shard := &affinityShard{workerID: workerID}
+	a.members[workerID] = shard
+	a.dirty = true
+	if !a.running {
+		a.running = true
+		go a.dispatch()
+	}
+	a.cond.Broadcast()
+	return shard
+}
+
+// Remove the queue of a Process routine. Messages left in the queue are routed again.
+func (a *affinity) detach(shard *affinityShard) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !shard.retired {
+		delete(a.members, shard.workerID)
+		a.dirty = true
+	}
+	a.pending -= shard.pending
+	if len(shard.queue) > 0 {
+		a.requeue = append(append([]*ServiceMessage{}, shard.queue...), a.requeue...)
+		select {
+		case a.wake <- struct{}{}:
+		default:
+		}
+	}
+	shard.queue = nil
+	shard.pending = 0
+	a.cond.Broadcast()
+}
+
+// Wait for the next message of a Process routine.
+func (a *affinity) receive(shard *affinityShard) *ServiceMessage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for len(shard.queue) == 0 {
+		a.cond.Wait()
+	}
+	msg := shard.queue[0]
+	shard.queue = shard.queue[1:]
+	return msg
+}
+
+// Wait for the next message of a Process routine until deadline. Return nil if none arrived.
+func (a *affinity) receiveUntil(shard *affinityShard, deadline time.Time) *ServiceMessage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for len(shard.queue) == 0 {
+		if !time.Now().Before(deadline) {
+			return nil
+		}
+		a.cond.Wait()
+	}
+	msg := shard.queue[0]
+	shard.queue = shard.queue[1:]
+	return msg
+}
+
+// Wake up all routines waiting for messages.
+func (a *affinity) wakeAll() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cond.Broadcast()
+}
+
+// Mark a received message as processed.
+func (a *affinity) done(shard *affinityShard) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	shard.pending--
+	a.pending--
+	a.cond.Broadcast()
+}
+
+// Return number of messages waiting to be processed.
+func (a *affinity) queued() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	count := len(a.requeue)
+	for _, shard := range a.members {
+		count += len(shard.queue)
+	}
+	for _, shard := range a.shards {
+		if shard.retired {
+			count += len(shard.queue)
+		}
+	}
+	return count
+}
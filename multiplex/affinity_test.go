@@ -0,0 +1,152 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestServiceCore_SetAffinity(t *testing.T) {
+	svc := NewAffinityService(diag.NewDebugLogger(10))
+	assert.NoError(t, svc.SetAffinity(nil))
+	svc.SetWorker(4)
+	assert.True(t, svc.WaitWorker(time.Second))
+	svc.sendAll(10, 0, 50)
+	assert.True(t, waitCondition(func() bool { return svc.count() == 500 }))
+	svc.assertOrdered(t)
+	for key, workers := range svc.workers() {
+		assert.Len(t, workers, 1, "key %s must be processed by a single worker", key)
+	}
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+	assert.True(t, waitCondition(svc.i.affinity.stopped), "dispatch routine must stop with the last Process routine")
+
+	svc.SetWorker(2)
+	svc.sendAll(10, 50, 60)
+	assert.True(t, waitCondition(func() bool { return svc.count() == 600 }))
+	svc.assertOrdered(t)
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+	assert.True(t, waitCondition(svc.i.affinity.stopped))
+}
+
+func TestServiceCore_SetAffinity_Reshard(t *testing.T) {
+	svc := NewAffinityService(diag.NewDebugLogger(10))
+	assert.NoError(t, svc.SetAffinity(AffinityParam("customer")))
+	svc.SetWorker(4)
+	svc.sendAll(10, 0, 30)
+	svc.SetWorker(2)
+	svc.sendAll(10, 30, 60)
+	svc.SetWorker(6)
+	svc.sendAll(10, 60, 90)
+	assert.True(t, waitCondition(func() bool { return svc.count() == 900 }))
+	assert.True(t, svc.WaitWorker(time.Second))
+	svc.assertOrdered(t)
+	assert.Equal(t, 0, svc.QueueLength())
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestServiceCore_SetAffinity_Started(t *testing.T) {
+	svc := NewAffinityService(diag.NewDebugLogger(10))
+	svc.SetWorker(1)
+	assert.ErrorIs(t, svc.SetAffinity(nil), ErrWorkersStarted)
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestAffinityParam(t *testing.T) {
+	keyFunc := AffinityParam("customer")
+	assert.Equal(t, "", keyFunc(&ServiceMessage{}))
+	assert.Equal(t, "42", keyFunc(&ServiceMessage{Params: ExecParams{"customer": 42}}))
+}
+
+type AffinityService struct {
+	ServiceCore
+	i *ServiceCoreInternal
+
+	mu        sync.Mutex
+	processed map[string][]int
+	handledBy map[string]map[uint64]bool
+}
+
+func NewAffinityService(logger diag.Logger) *AffinityService {
+	svc := &AffinityService{
+		processed: make(map[string][]int),
+		handledBy: make(map[string]map[uint64]bool),
+	}
+	svc.i = svc.InitServiceCore("Affinity", logger, svc.coreProcessHook)
+	return svc
+}
+
+func (s *AffinityService) coreProcessHook(workerID uint64, msg *ServiceMessage) *HookState {
+	if msg.Command != "order" {
+		return &HookState{Handled: false}
+	}
+	time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+	key := msg.GetParam("customer", "").(string)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processed[key] = append(s.processed[key], msg.GetParam("seq", 0).(int))
+	if s.handledBy[key] == nil {
+		s.handledBy[key] = make(map[uint64]bool)
+	}
+	s.handledBy[key][workerID] = true
+	return &HookState{Handled: true}
+}
+
+func (s *AffinityService) sendAll(keys, from, to int) {
+	for seq := from; seq < to; seq++ {
+		for key := 0; key < keys; key++ {
+			customer := "customer" + strconv.Itoa(key)
+			s.Exec("order", ExecParams{"customer": customer, AffinityKey: customer, "seq": seq})
+		}
+	}
+}
+
+func (s *AffinityService) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, seqs := range s.processed {
+		count += len(seqs)
+	}
+	return count
+}
+
+func (s *AffinityService) workers() map[string]map[uint64]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handledBy
+}
+
+func (s *AffinityService) assertOrdered(t *testing.T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, seqs := range s.processed {
+		for i, seq := range seqs {
+			if !assert.Equal(t, i, seq, "messages of %s must be processed in order", key) {
+				return
+			}
+		}
+	}
+}
+
+func (a *affinity) stopped() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return !a.running
+}
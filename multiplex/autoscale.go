@@ -0,0 +1,203 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+// ScalableService defines functions required by Autoscaler to observe and resize a service.
+// All services embedding ServiceCore satisfy this interface.
+//
+// Available since v0.11.0
+type ScalableService interface {
+	// Return Service Identifier string.
+	ServiceID() string
+	// Set number of Process routines the service should use to handle requests.
+	SetWorker(workerCount uint64)
+	// Return number of Process routines the service is managing.
+	WorkerCount() uint64
+	// Return number of pending requests in the queue.
+	QueueLength() int
+	// Return moving average of time spent to process a request.
+	Latency() time.Duration
+}
+
+// AutoscaleConfig defines bounds and thresholds used by Autoscaler.
+//
+// Available since v0.11.0
+type AutoscaleConfig struct {
+	// Minimum number of workers. Default to 1.
+	MinWorker uint64
+	// Maximum number of workers. Default to MinWorker.
+	MaxWorker uint64
+
+	// Scale up when pending requests per worker reach this value. Zero disables the rule.
+	ScaleUpQueueLength int
+	// Scale up when processing latency reaches this value. Zero disables the rule.
+	ScaleUpLatency time.Duration
+	// Scale down when pending requests per worker are not greater than this value.
+	ScaleDownQueueLength int
+	// Scale down only when processing latency is not greater than this value. Zero disables the rule.
+	ScaleDownLatency time.Duration
+
+	// Number of workers added for each scale up. Default to 1.
+	ScaleUpStep uint64
+	// Number of workers removed for each scale down. Default to 1.
+	ScaleDownStep uint64
+
+	// Minimum duration since last resize before scaling up again.
+	ScaleUpCooldown time.Duration
+	// Minimum duration since last resize before scaling down again.
+	ScaleDownCooldown time.Duration
+
+	// Duration between two evaluations. Default to 1 second.
+	Interval time.Duration
+}
+
+// Autoscaler watches queue length and processing latency of a service and adjusts
+// its number of workers within configured bounds.
+//
+// Available since v0.11.0
+type Autoscaler struct {
+	service ScalableService
+	config  AutoscaleConfig
+	logger  diag.Logger
+
+	mu         sync.Mutex
+	lastScaled time.Time
+	stopChan   chan struct{}
+	now        func() time.Time
+}
+
+// Return new Autoscaler for the service.
+//
+// Available since v0.11.0
+func NewAutoscaler(service ScalableService, config AutoscaleConfig, logger diag.Logger) *Autoscaler {
+	if config.MinWorker == 0 {
+		config.MinWorker = 1
+	}
+	if config.MaxWorker < config.MinWorker {
+		config.MaxWorker = config.MinWorker
+	}
+	if config.ScaleUpStep == 0 {
+		config.ScaleUpStep = 1
+	}
+	if config.ScaleDownStep == 0 {
+		config.ScaleDownStep = 1
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	return &Autoscaler{
+		service: service,
+		config:  config,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Start evaluating the service periodically in a separate routine.
+// Calling Start on a running Autoscaler does nothing.
+//
+// Available since v0.11.0
+func (a *Autoscaler) Start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stopChan != nil {
+		return
+	}
+	a.stopChan = make(chan struct{})
+	go a.run(a.stopChan)
+}
+
+// Stop the periodic evaluation. Number of workers is kept as is.
+//
+// Available since v0.11.0
+func (a *Autoscaler) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stopChan == nil {
+		return
+	}
+	close(a.stopChan)
+	a.stopChan = nil
+}
+
+// Evaluate the service immediately, resize it if needed and return the number of workers requested.
+//
+// Available since v0.11.0
+func (a *Autoscaler) Evaluate() uint64 {
+	current, target := a.target()
+	if target != current {
+		// SetWorker may wait for space in the queue of the service, so it is called without lock.
+		a.logger.Infof("%s: Autoscaler resized workers from %d to %d.", a.service.ServiceID(), current, target)
+		a.service.SetWorker(target)
+	}
+	return target
+}
+
+// Return the current number of workers and the number of workers requested by the evaluation.
+// The time of scaling is recorded if they differ.
+func (a *Autoscaler) target() (uint64, uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cfg := a.config
+	now := a.now()
+	current := a.service.WorkerCount()
+	target := current
+	if current < cfg.MinWorker {
+		target = cfg.MinWorker
+	} else if current > cfg.MaxWorker {
+		target = cfg.MaxWorker
+	} else {
+		divisor := current
+		if divisor == 0 {
+			divisor = 1
+		}
+		queuePerWorker := a.service.QueueLength() / int(divisor)
+		latency := a.service.Latency()
+		sinceLast := now.Sub(a.lastScaled)
+		scaleUp := (cfg.ScaleUpQueueLength > 0 && queuePerWorker >= cfg.ScaleUpQueueLength) ||
+			(cfg.ScaleUpLatency > 0 && latency >= cfg.ScaleUpLatency)
+		scaleDown := queuePerWorker <= cfg.ScaleDownQueueLength &&
+			(cfg.ScaleDownLatency == 0 || latency <= cfg.ScaleDownLatency)
+		if scaleUp && current < cfg.MaxWorker && sinceLast >= cfg.ScaleUpCooldown {
+			target = current + cfg.ScaleUpStep
+			if target > cfg.MaxWorker {
+				target = cfg.MaxWorker
+			}
+		} else if !scaleUp && scaleDown && current > cfg.MinWorker && sinceLast >= cfg.ScaleDownCooldown {
+			target = cfg.MinWorker
+			if current-cfg.MinWorker > cfg.ScaleDownStep {
+				target = current - cfg.ScaleDownStep
+			}
+		}
+	}
+	if target != current {
+		a.lastScaled = now
+	}
+	return current, target
+}
+
+// Evaluation routine of the Autoscaler.
+func (a *Autoscaler) run(stopChan chan struct{}) {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	a.Evaluate()
+	for {
+		select {
+		case <-ticker.C:
+			a.Evaluate()
+		case <-stopChan:
+			return
+		}
+	}
+}
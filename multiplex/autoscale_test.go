@@ -0,0 +1,129 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestAutoscaler_Bounds(t *testing.T) {
+	svc := &fakeScalableService{workers: 0}
+	scaler := NewAutoscaler(svc, AutoscaleConfig{MinWorker: 2, MaxWorker: 4}, diag.NewDebugLogger(10))
+	assert.Equal(t, uint64(2), scaler.Evaluate(), "must scale up to MinWorker")
+	svc.workers = 10
+	assert.Equal(t, uint64(4), scaler.Evaluate(), "must scale down to MaxWorker")
+}
+
+func TestAutoscaler_QueueLength(t *testing.T) {
+	now := time.Now()
+	svc := &fakeScalableService{workers: 2, queue: 20}
+	scaler := NewAutoscaler(svc, AutoscaleConfig{
+		MinWorker:          1,
+		MaxWorker:          4,
+		ScaleUpQueueLength: 10,
+		ScaleUpCooldown:    time.Minute,
+		ScaleDownCooldown:  time.Minute,
+	}, diag.NewDebugLogger(10))
+	scaler.now = func() time.Time { return now }
+	assert.Equal(t, uint64(3), scaler.Evaluate(), "must scale up when queue is long")
+	svc.queue = 60
+	assert.Equal(t, uint64(3), scaler.Evaluate(), "must respect cooldown")
+	now = now.Add(time.Minute)
+	assert.Equal(t, uint64(4), scaler.Evaluate(), "must scale up after cooldown")
+	now = now.Add(time.Minute)
+	assert.Equal(t, uint64(4), scaler.Evaluate(), "must not exceed MaxWorker")
+	svc.queue = 0
+	now = now.Add(time.Minute)
+	assert.Equal(t, uint64(3), scaler.Evaluate(), "must scale down when queue is empty")
+}
+
+func TestAutoscaler_Latency(t *testing.T) {
+	svc := &fakeScalableService{workers: 1, latency: 50 * time.Millisecond}
+	scaler := NewAutoscaler(svc, AutoscaleConfig{
+		MinWorker:        1,
+		MaxWorker:        8,
+		ScaleUpLatency:   20 * time.Millisecond,
+		ScaleDownLatency: 5 * time.Millisecond,
+		ScaleUpStep:      3,
+	}, diag.NewDebugLogger(10))
+	assert.Equal(t, uint64(4), scaler.Evaluate(), "must scale up when latency is high")
+	svc.latency = 10 * time.Millisecond
+	assert.Equal(t, uint64(4), scaler.Evaluate(), "must hold when latency is moderate")
+	svc.latency = time.Millisecond
+	assert.Equal(t, uint64(3), scaler.Evaluate(), "must scale down when latency is low")
+}
+
+func TestAutoscaler_StartStop(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewIdleService(logger)
+	scaler := NewAutoscaler(svc, AutoscaleConfig{
+		MinWorker: 2,
+		MaxWorker: 2,
+		Interval:  10 * time.Millisecond,
+	}, logger)
+	scaler.Start()
+	scaler.Start()
+	time.Sleep(50 * time.Millisecond)
+	scaler.Stop()
+	scaler.Stop()
+	assert.True(t, svc.WaitWorker(time.Second), "workers must be started")
+	assert.Equal(t, uint64(2), svc.WorkerCount(), "mismatch worker count")
+}
+
+func TestAutoscaler_Evaluate_Unlocked(t *testing.T) {
+	svc := &blockingScalableService{
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	scaler := NewAutoscaler(svc, AutoscaleConfig{MinWorker: 2, MaxWorker: 4}, diag.NewDebugLogger(10))
+	done := make(chan uint64, 1)
+	go func() {
+		done <- scaler.Evaluate()
+	}()
+	<-svc.entered
+	stopped := make(chan struct{})
+	go func() {
+		scaler.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop must not wait for SetWorker")
+	}
+	close(svc.release)
+	assert.Equal(t, uint64(2), <-done)
+	assert.Equal(t, uint64(2), svc.workers)
+}
+
+type fakeScalableService struct {
+	workers uint64
+	queue   int
+	latency time.Duration
+}
+
+func (s *fakeScalableService) ServiceID() string            { return "Fake" }
+func (s *fakeScalableService) SetWorker(workerCount uint64) { s.workers = workerCount }
+func (s *fakeScalableService) WorkerCount() uint64          { return s.workers }
+func (s *fakeScalableService) QueueLength() int             { return s.queue }
+func (s *fakeScalableService) Latency() time.Duration       { return s.latency }
+
+type blockingScalableService struct {
+	fakeScalableService
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingScalableService) SetWorker(workerCount uint64) {
+	close(s.entered)
+	<-s.release
+	s.workers = workerCount
+}
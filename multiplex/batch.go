@@ -0,0 +1,162 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"runtime/debug"
+	"time"
+)
+
+// ErrNoBatchResult is returned to the sender when BatchHook doesn't return a result for its message.
+//
+// Available since v0.11.0
+var ErrNoBatchResult = errors.New("no batch result")
+
+// BatchResult is the outcome of a message processed by BatchHook.
+//
+// Available since v0.11.0
+type BatchResult struct {
+	Result interface{}
+	Err    error
+}
+
+// BatchHook processes a batch of messages and returns their outcomes in the same order.
+//
+// Available since v0.11.0
+type BatchHook func(workerID uint64, msgs []*ServiceMessage) []BatchResult
+
+// batchConfig stores batch mode options of a ServiceCore.
+type batchConfig struct {
+	size int
+	wait time.Duration
+	hook BatchHook
+}
+
+// Process requests in batches of at most size messages.
+// After receiving a message, the Process routine keeps collecting messages until the batch is full
+// or wait elapsed, then hands the batch to hook. Non-positive wait means only messages already queued are collected.
+// Each BatchResult is returned to the sender of the message at the same index,
+// messages without a BatchResult receive ErrNoBatchResult.
+// The exit request is never batched. Interceptors are not applied to batches.
+// Passing nil hook switches the service back to CoreProcessHook.
+//
+// Available since v0.11.0
+func (s ServiceCore) SetBatchHook(size int, wait time.Duration, hook BatchHook) {
+	if hook == nil {
+		s.i.batch.Store((*batchConfig)(nil))
+		return
+	}
+	if size < 1 {
+		size = 1
+	}
+	s.i.batch.Store(&batchConfig{
+		size: size,
+		wait: wait,
+		hook: hook,
+	})
+}
+
+// Return batch mode options, nil if batch mode is disabled.
+func (i *ServiceCoreInternal) batchConfig() *batchConfig {
+	batch, _ := i.batch.Load().(*batchConfig)
+	return batch
+}
+
+// Collect a batch starting with msg then process it with BatchHook.
+// Return the exit request received while collecting, if any.
+// If the service is supervised, PanicError is returned if BatchHook panicked,
+// all messages of the batch will receive the same error. Otherwise the panic is propagated.
+func (s ServiceCore) processBatch(ctx *ProcessContext, shard *affinityShard, batch *batchConfig, msg *ServiceMessage) (next *ServiceMessage, err error) {
+	msgs := []*ServiceMessage{msg}
+	deadline := time.Now().Add(batch.wait)
+	var expired <-chan time.Time
+	if batch.wait > 0 {
+		timer := time.NewTimer(batch.wait)
+		defer timer.Stop()
+		expired = timer.C
+		if shard != nil {
+			wake := time.AfterFunc(batch.wait, s.i.affinity.wakeAll)
+			defer wake.Stop()
+		}
+	}
+	for len(msgs) < batch.size {
+		msg := s.i.receiveUntil(shard, expired, deadline)
+		if msg == nil {
+			break
+		}
+		if msg.Command == "exit" {
+			next = msg
+			break
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if shard != nil {
+		defer func() {
+			for range msgs {
+				s.i.affinity.done(shard)
+			}
+		}()
+	}
+	defer func() {
+		if !s.i.supervised() {
+			return
+		}
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+			for _, msg := range msgs {
+				msg.ReturnError(err)
+				s.i.observeOutcome(msg, &HookState{Handled: true, Error: err})
+				s.i.processed(msg)
+			}
+		}
+	}()
+	start := time.Now()
+	results := batch.hook(ctx.WorkerID, msgs)
+	s.i.observeLatency(time.Since(start) / time.Duration(len(msgs)))
+	for i, msg := range msgs {
+		result := BatchResult{Err: ErrNoBatchResult}
+		if i < len(results) {
+			result = results[i]
+		}
+		if result.Err != nil {
+			msg.ReturnError(result.Err)
+		} else {
+			msg.Return(result.Result)
+		}
+		state := &HookState{Handled: true, Error: result.Err}
+		s.i.acknowledge(msg, state)
+		s.i.observeOutcome(msg, state)
+		s.i.processed(msg)
+	}
+	return next, nil
+}
+
+// Return the next request for the Process routine, or nil if none arrived before expired.
+func (i *ServiceCoreInternal) receiveUntil(shard *affinityShard, expired <-chan time.Time, deadline time.Time) *ServiceMessage {
+	if shard != nil {
+		return i.affinity.receiveUntil(shard, deadline)
+	}
+	if expired == nil {
+		select {
+		case msg := <-i.MainChan:
+			return msg
+		default:
+			return nil
+		}
+	}
+	select {
+	case msg := <-i.MainChan:
+		return msg
+	case <-expired:
+		return nil
+	}
+}
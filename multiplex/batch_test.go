@@ -0,0 +1,151 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestServiceCore_SetBatchHook(t *testing.T) {
+	svc := NewBatchService(diag.NewDebugLogger(10))
+	svc.SetBatchHook(4, 50*time.Millisecond, svc.batchHook)
+	params := make([]ExecParams, 10)
+	for i := range params {
+		params[i] = ExecParams{"value": i}
+		params[i].ExpectReturn()
+		svc.Exec("square", params[i])
+	}
+	svc.SetWorker(1)
+	for i, p := range params {
+		if i == 3 {
+			p.Wait()
+			assert.Equal(t, errRejectedValue, p.ReturnErr())
+			continue
+		}
+		assert.Equal(t, i*i, p.WaitForReturn())
+	}
+	assert.Equal(t, []int{4, 4, 2}, svc.sizes())
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestServiceCore_SetBatchHook_Wait(t *testing.T) {
+	svc := NewBatchService(diag.NewDebugLogger(10))
+	svc.SetBatchHook(100, 20*time.Millisecond, svc.batchHook)
+	svc.SetWorker(1)
+	params := ExecParams{"value": 5}
+	params.ExpectReturn()
+	start := time.Now()
+	svc.Exec("square", params)
+	assert.Equal(t, 25, params.WaitForReturn())
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, []int{1}, svc.sizes())
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestServiceCore_SetBatchHook_MissingResult(t *testing.T) {
+	svc := NewBatchService(diag.NewDebugLogger(10))
+	svc.SetBatchHook(2, time.Second, func(workerID uint64, msgs []*ServiceMessage) []BatchResult {
+		return []BatchResult{{Result: "first"}}
+	})
+	first, second := ExecParams{}, ExecParams{}
+	first.ExpectReturn()
+	second.ExpectReturn()
+	svc.Exec("any", first)
+	svc.Exec("any", second)
+	svc.SetWorker(1)
+	assert.Equal(t, "first", first.WaitForReturn())
+	second.Wait()
+	assert.True(t, errors.Is(second.ReturnErr(), ErrNoBatchResult))
+
+	svc.SetBatchHook(0, 0, nil)
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestServiceCore_SetBatchHook_Panic(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	svc := NewBatchService(logger)
+	svc.SetRouter(controller)
+	controller.Register(svc)
+	assert.NoError(t, controller.Supervise("Batch", SupervisorSpec{}))
+	assert.NoError(t, svc.SetAffinity(nil))
+	svc.SetBatchHook(2, time.Second, func(workerID uint64, msgs []*ServiceMessage) []BatchResult {
+		if msgs[0].Command == "crash" {
+			panic("batch")
+		}
+		return make([]BatchResult, len(msgs))
+	})
+	first, second := ExecParams{AffinityKey: "a"}, ExecParams{AffinityKey: "a"}
+	first.ExpectReturn()
+	second.ExpectReturn()
+	svc.Exec("crash", first)
+	svc.Exec("crash", second)
+	svc.SetWorker(1)
+	for _, p := range []ExecParams{first, second} {
+		p.Wait()
+		var panicErr *PanicError
+		assert.True(t, errors.As(p.ReturnErr(), &panicErr))
+	}
+
+	third := ExecParams{AffinityKey: "a"}
+	third.ExpectReturn()
+	svc.Exec("any", third)
+	third.Wait()
+	assert.NoError(t, third.ReturnErr(), "key of the crashed batch must be routed again")
+	assert.Equal(t, 0, svc.QueueLength())
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+var errRejectedValue = errors.New("value 3 is not allowed")
+
+type BatchService struct {
+	ServiceCore
+	i *ServiceCoreInternal
+
+	mu      sync.Mutex
+	batches []int
+}
+
+func NewBatchService(logger diag.Logger) *BatchService {
+	svc := &BatchService{}
+	svc.i = svc.InitServiceCore("Batch", logger, nil)
+	return svc
+}
+
+func (s *BatchService) batchHook(workerID uint64, msgs []*ServiceMessage) []BatchResult {
+	s.mu.Lock()
+	s.batches = append(s.batches, len(msgs))
+	s.mu.Unlock()
+	results := make([]BatchResult, len(msgs))
+	for i, msg := range msgs {
+		value := msg.GetParam("value", 0).(int)
+		if value == 3 {
+			results[i].Err = errRejectedValue
+			continue
+		}
+		results[i].Result = value * value
+	}
+	return results
+}
+
+func (s *BatchService) sizes() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int{}, s.batches...)
+}
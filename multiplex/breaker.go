@@ -0,0 +1,450 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+// ErrCircuitOpen is returned when a call is short-circuited by an open CircuitBreaker,
+// and is matched by errors.Is for all CircuitOpenError.
+//
+// Available since v0.11.0
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitOpenError is returned when a message is not forwarded because
+// the CircuitBreaker of the target service is open.
+//
+// Available since v0.11.0
+type CircuitOpenError struct {
+	ServiceID string
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit of service %q is open", e.ServiceID)
+}
+
+// Report whether target is ErrCircuitOpen.
+//
+// Available since v0.11.0
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitState is the state of a CircuitBreaker.
+//
+// Available since v0.11.0
+type CircuitState int8
+
+const (
+	// Calls are allowed and their outcomes are tracked.
+	CircuitClosed CircuitState = iota
+
+	// Calls are short-circuited until OpenTimeout elapsed.
+	CircuitOpen
+
+	// A limited number of trial calls are allowed to probe whether the target recovered.
+	CircuitHalfOpen
+)
+
+// Return name of the state.
+//
+// Available since v0.11.0
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int8(s))
+}
+
+// CircuitBreakerConfig defines thresholds and callbacks of a CircuitBreaker.
+//
+// Available since v0.11.0
+type CircuitBreakerConfig struct {
+	// Name used in logs.
+	Name string
+	// Trip after this number of consecutive failures. Zero disables this threshold.
+	// Default to 5 if FailureRate is also zero.
+	ConsecutiveFailures int
+	// Trip when the ratio of failures among the last WindowSize calls reaches this value, from 0 to 1.
+	// Zero disables this threshold.
+	FailureRate float64
+	// Number of recent calls considered by FailureRate. Default to 20.
+	WindowSize int
+	// Minimum number of calls in the window before FailureRate applies. Default to WindowSize / 2.
+	MinCalls int
+	// Time the circuit stays open before allowing trial calls. Default to 5 seconds.
+	OpenTimeout time.Duration
+	// Number of trial calls allowed in half-open state. The circuit closes once all of them succeeded.
+	// Default to 1.
+	HalfOpenCalls int
+	// Report whether the error counts as a failure. Default to all non-nil errors.
+	IsFailure func(err error) bool
+	// Called after the state changed.
+	OnStateChange func(name string, from, to CircuitState)
+	// Logger receiving state changes. Optional.
+	Logger diag.Logger
+}
+
+// CircuitBreakerStats is a snapshot of CircuitBreaker metrics.
+//
+// Available since v0.11.0
+type CircuitBreakerStats struct {
+	// Current state.
+	State CircuitState
+	// Number of calls allowed.
+	Allowed uint64
+	// Number of calls short-circuited.
+	ShortCircuited uint64
+	// Number of calls reported as succeeded.
+	Successes uint64
+	// Number of calls reported as failed.
+	Failures uint64
+	// Number of times the circuit opened.
+	Opened uint64
+	// Current number of consecutive failures.
+	ConsecutiveFailures int
+	// Time of the last state change.
+	Changed time.Time
+}
+
+// CircuitBreaker stops calls to a failing target for a while so it can recover,
+// instead of piling up work that will likely fail. It is safe for concurrent use.
+//
+// Available since v0.11.0
+type CircuitBreaker struct {
+	mu     sync.Mutex
+	config CircuitBreakerConfig
+	now    func() time.Time
+
+	state       CircuitState
+	changed     time.Time
+	consecutive int
+	// Outcomes of recent calls in closed state, true for failures.
+	window   []bool
+	next     int
+	calls    int
+	failures int
+	// Trial calls allowed and succeeded in half-open state.
+	trials    int
+	succeeded int
+
+	allowed        uint64
+	shortCircuited uint64
+	successes      uint64
+	failed         uint64
+	opened         uint64
+}
+
+// Return new CircuitBreaker in closed state.
+//
+// Available since v0.11.0
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.ConsecutiveFailures <= 0 && config.FailureRate <= 0 {
+		config.ConsecutiveFailures = 5
+	}
+	if config.WindowSize <= 0 {
+		config.WindowSize = 20
+	}
+	if config.MinCalls <= 0 {
+		config.MinCalls = config.WindowSize / 2
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 5 * time.Second
+	}
+	if config.HalfOpenCalls <= 0 {
+		config.HalfOpenCalls = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(err error) bool {
+			return err != nil
+		}
+	}
+	return &CircuitBreaker{
+		config:  config,
+		now:     time.Now,
+		changed: time.Now(),
+		window:  make([]bool, config.WindowSize),
+	}
+}
+
+// Return ErrCircuitOpen if the call must be short-circuited, otherwise nil.
+// The outcome of an allowed call must be reported with Record.
+//
+// Available since v0.11.0
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	var change func()
+	defer func() {
+		b.mu.Unlock()
+		if change != nil {
+			change()
+		}
+	}()
+	change = b.refresh()
+	switch b.state {
+	case CircuitOpen:
+		b.shortCircuited++
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.trials >= b.config.HalfOpenCalls {
+			b.shortCircuited++
+			return ErrCircuitOpen
+		}
+		b.trials++
+	}
+	b.allowed++
+	return nil
+}
+
+// Report the outcome of an allowed call.
+//
+// Available since v0.11.0
+func (b *CircuitBreaker) Record(err error) {
+	failure := b.config.IsFailure(err)
+	b.mu.Lock()
+	var change func()
+	defer func() {
+		b.mu.Unlock()
+		if change != nil {
+			change()
+		}
+	}()
+	if failure {
+		b.failed++
+		b.consecutive++
+	} else {
+		b.successes++
+		b.consecutive = 0
+	}
+	switch b.state {
+	case CircuitClosed:
+		b.observe(failure)
+		if b.tripped() {
+			change = b.transition(CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		if failure {
+			change = b.transition(CircuitOpen)
+		} else if b.succeeded++; b.succeeded >= b.config.HalfOpenCalls {
+			change = b.transition(CircuitClosed)
+		}
+	}
+}
+
+// Call fn if allowed and record its outcome.
+// Return ErrCircuitOpen without calling fn if the call is short-circuited.
+//
+// Available since v0.11.0
+func (b *CircuitBreaker) Execute(fn func() error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	err := fn()
+	b.Record(err)
+	return err
+}
+
+// Return current state.
+//
+// Available since v0.11.0
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	change := b.refresh()
+	state := b.state
+	b.mu.Unlock()
+	if change != nil {
+		change()
+	}
+	return state
+}
+
+// Return a snapshot of metrics.
+//
+// Available since v0.11.0
+func (b *CircuitBreaker) Stats() CircuitBreakerStats {
+	b.mu.Lock()
+	change := b.refresh()
+	stats := CircuitBreakerStats{
+		State:               b.state,
+		Allowed:             b.allowed,
+		ShortCircuited:      b.shortCircuited,
+		Successes:           b.successes,
+		Failures:            b.failed,
+		Opened:              b.opened,
+		ConsecutiveFailures: b.consecutive,
+		Changed:             b.changed,
+	}
+	b.mu.Unlock()
+	if change != nil {
+		change()
+	}
+	return stats
+}
+
+// Close the circuit and forget recent outcomes. Metrics are kept.
+//
+// Available since v0.11.0
+func (b *CircuitBreaker) Reset() {
+	b.mu.Lock()
+	change := b.transition(CircuitClosed)
+	b.mu.Unlock()
+	if change != nil {
+		change()
+	}
+}
+
+// Move from open to half-open state once OpenTimeout elapsed. Must be called with lock held.
+func (b *CircuitBreaker) refresh() func() {
+	if b.state == CircuitOpen && !b.now().Before(b.changed.Add(b.config.OpenTimeout)) {
+		return b.transition(CircuitHalfOpen)
+	}
+	return nil
+}
+
+// Record the outcome of a call in closed state into the window. Must be called with lock held.
+func (b *CircuitBreaker) observe(failure bool) {
+	if b.calls == len(b.window) {
+		if b.window[b.next] {
+			b.failures--
+		}
+	} else {
+		b.calls++
+	}
+	b.window[b.next] = failure
+	if failure {
+		b.failures++
+	}
+	b.next = (b.next + 1) % len(b.window)
+}
+
+// Return whether a threshold is reached. Must be called with lock held.
+func (b *CircuitBreaker) tripped() bool {
+	if b.config.ConsecutiveFailures > 0 && b.consecutive >= b.config.ConsecutiveFailures {
+		return true
+	}
+	if b.config.FailureRate > 0 && b.calls >= b.config.MinCalls {
+		return float64(b.failures)/float64(b.calls) >= b.config.FailureRate
+	}
+	return false
+}
+
+// Change state and reset counters of the new state. Must be called with lock held.
+// Return the notification to run after the lock is released, nil if the state is unchanged.
+func (b *CircuitBreaker) transition(to CircuitState) func() {
+	from := b.state
+	b.state = to
+	b.changed = b.now()
+	b.trials = 0
+	b.succeeded = 0
+	if to == CircuitClosed {
+		b.consecutive = 0
+		b.calls = 0
+		b.failures = 0
+		b.next = 0
+	}
+	if from == to {
+		return nil
+	}
+	if to == CircuitOpen {
+		b.opened++
+	}
+	config := b.config
+	return func() {
+		if config.Logger != nil {
+			if to == CircuitOpen {
+				config.Logger.Warnf("Circuit %s: State changed from %s to %s.", config.Name, from, to)
+			} else {
+				config.Logger.Infof("Circuit %s: State changed from %s to %s.", config.Name, from, to)
+			}
+		}
+		if config.OnStateChange != nil {
+			config.OnStateChange(config.Name, from, to)
+		}
+	}
+}
+
+// Protect serviceID with breaker. Messages forwarded to the service are short-circuited
+// with CircuitOpenError while the circuit is open, the exit request is never short-circuited.
+// Outcomes of requests admitted by the breaker are recorded once the service processed them,
+// a request fails if its HookState has an error. Requests sent with Exec directly are not recorded.
+// Forwarding errors are recorded as failures too.
+// Passing nil breaker removes the protection.
+// NoSuchServiceError is returned if the service is not registered.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) SetCircuitBreaker(serviceID string, breaker *CircuitBreaker) error {
+	service, found := s.c.Service(serviceID)
+	if !found {
+		return &NoSuchServiceError{ServiceID: serviceID}
+	}
+	if _, ok := service.(interface{ serviceCore() ServiceCore }); !ok {
+		return fmt.Errorf("service %q doesn't embed ServiceCore", serviceID)
+	}
+	s.breakersMu.Lock()
+	defer s.breakersMu.Unlock()
+	if breaker == nil {
+		delete(s.breakers, serviceID)
+	} else {
+		s.breakers[serviceID] = breaker
+	}
+	return nil
+}
+
+// Return the CircuitBreaker protecting serviceID, or nil if there is none.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) CircuitBreaker(serviceID string) *CircuitBreaker {
+	s.breakersMu.RLock()
+	defer s.breakersMu.RUnlock()
+	return s.breakers[serviceID]
+}
+
+// Parameter key carrying the CircuitBreaker which admitted a message until the target service receives it.
+const circuitKey = "circuit_breaker"
+
+// Forward the message through the chain unless the circuit of serviceID is open.
+// Admitted messages carry the breaker in their params so the target service records their outcomes.
+func (s *ServiceRouter) guardedForward(serviceID string, msg *ServiceMessage) error {
+	breaker := s.CircuitBreaker(serviceID)
+	if breaker == nil || msg.Command == "exit" {
+		return s.forwardFunc()(serviceID, msg)
+	}
+	if breaker.Allow() != nil {
+		return &CircuitOpenError{ServiceID: serviceID}
+	}
+	msg.Params = msg.Params.Clone()
+	msg.Params[circuitKey] = breaker
+	err := s.forwardFunc()(serviceID, msg)
+	if err != nil {
+		delete(msg.Params, circuitKey)
+		breaker.Record(err)
+	}
+	return err
+}
+
+// Remove the CircuitBreaker which admitted the message from params and return it, nil if there is none.
+func takeCircuit(params ExecParams) *CircuitBreaker {
+	breaker, ok := params[circuitKey].(*CircuitBreaker)
+	if ok {
+		delete(params, circuitKey)
+	}
+	return breaker
+}
@@ -0,0 +1,226 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+var errBreakerTest = errors.New("failed")
+
+func TestCircuitBreaker_ConsecutiveFailures(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	var changes []string
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		Name:                "Test",
+		ConsecutiveFailures: 3,
+		Logger:              logger,
+		OnStateChange: func(name string, from, to CircuitState) {
+			changes = append(changes, name+":"+from.String()+">"+to.String())
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, breaker.Execute(func() error { return errBreakerTest }), errBreakerTest)
+	}
+	assert.NoError(t, breaker.Execute(func() error { return nil }), "success resets consecutive failures")
+	for i := 0; i < 2; i++ {
+		breaker.Execute(func() error { return errBreakerTest })
+	}
+	assert.Equal(t, CircuitClosed, breaker.State())
+	breaker.Execute(func() error { return errBreakerTest })
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	called := false
+	err := breaker.Execute(func() error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.False(t, called, "open circuit must short-circuit")
+
+	stats := breaker.Stats()
+	assert.Equal(t, uint64(6), stats.Allowed)
+	assert.Equal(t, uint64(1), stats.ShortCircuited)
+	assert.Equal(t, uint64(5), stats.Failures)
+	assert.Equal(t, uint64(1), stats.Successes)
+	assert.Equal(t, uint64(1), stats.Opened)
+	assert.Equal(t, []string{"Test:closed>open"}, changes)
+	assert.Equal(t, "WARN Circuit Test: State changed from closed to open.", logger.LastMessage())
+}
+
+func TestCircuitBreaker_FailureRate(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureRate: 0.5,
+		WindowSize:  4,
+		MinCalls:    4,
+	})
+
+	outcomes := []error{errBreakerTest, nil, errBreakerTest}
+	for _, outcome := range outcomes {
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(outcome)
+	}
+	assert.Equal(t, CircuitClosed, breaker.State(), "not enough calls")
+
+	breaker.Allow()
+	breaker.Record(nil)
+	assert.Equal(t, CircuitOpen, breaker.State(), "2 failures out of 4 calls")
+}
+
+func TestCircuitBreaker_FailureRate_Window(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureRate: 0.75,
+		WindowSize:  4,
+		MinCalls:    4,
+	})
+
+	for _, outcome := range []error{errBreakerTest, errBreakerTest, nil, nil, nil, errBreakerTest, errBreakerTest} {
+		breaker.Allow()
+		breaker.Record(outcome)
+	}
+	assert.Equal(t, CircuitClosed, breaker.State(), "oldest failures must leave the window")
+	breaker.Allow()
+	breaker.Record(errBreakerTest)
+	assert.Equal(t, CircuitOpen, breaker.State())
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	now := time.Now()
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Second,
+		HalfOpenCalls:       2,
+	})
+	breaker.now = func() time.Time { return now }
+
+	breaker.Execute(func() error { return errBreakerTest })
+	assert.Equal(t, CircuitOpen, breaker.State())
+	now = now.Add(999 * time.Millisecond)
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	now = now.Add(time.Millisecond)
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+	assert.NoError(t, breaker.Allow())
+	assert.NoError(t, breaker.Allow())
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen, "trial calls are limited")
+	breaker.Record(nil)
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+	breaker.Record(nil)
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	breaker.Execute(func() error { return errBreakerTest })
+	now = now.Add(time.Second)
+	assert.NoError(t, breaker.Allow())
+	breaker.Record(errBreakerTest)
+	assert.Equal(t, CircuitOpen, breaker.State(), "failed trial must reopen the circuit")
+	assert.Equal(t, uint64(3), breaker.Stats().Opened)
+
+	breaker.Reset()
+	assert.Equal(t, CircuitClosed, breaker.State())
+}
+
+func TestCircuitBreaker_IsFailure(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		IsFailure: func(err error) bool {
+			return err != nil && !errors.Is(err, ErrNoSuchService)
+		},
+	})
+
+	breaker.Execute(func() error { return &NoSuchServiceError{ServiceID: "X"} })
+	assert.Equal(t, CircuitClosed, breaker.State())
+	breaker.Execute(func() error { return errBreakerTest })
+	assert.Equal(t, CircuitOpen, breaker.State())
+}
+
+func TestCircuitBreaker_Concurrency(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureRate: 0.9})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			breaker.Execute(func() error {
+				if i%2 == 0 {
+					return errBreakerTest
+				}
+				return nil
+			})
+			breaker.Stats()
+		}(i)
+	}
+	wg.Wait()
+
+	stats := breaker.Stats()
+	assert.Equal(t, CircuitClosed, stats.State)
+	assert.Equal(t, uint64(100), stats.Allowed)
+}
+
+func TestServiceRouter_SetCircuitBreaker(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	svc := NewIdleService(logger)
+	svc.i.CoreProcessHook = func(workerID uint64, msg *ServiceMessage) *HookState {
+		if msg.Command == "exit" {
+			return &HookState{Handled: false}
+		}
+		if msg.Command == "fail" {
+			msg.ReturnError(errBreakerTest)
+			return &HookState{Handled: true, Error: errBreakerTest}
+		}
+		msg.Return(msg.Command)
+		return &HookState{Handled: true}
+	}
+	svc.SetRouter(controller)
+	svc.SetWorker(1)
+	controller.Register(svc)
+	controller.Run(false)
+
+	assert.ErrorIs(t, controller.Router().SetCircuitBreaker("Unknown", NewCircuitBreaker(CircuitBreakerConfig{})), ErrNoSuchService)
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{ConsecutiveFailures: 2, OpenTimeout: time.Hour})
+	assert.NoError(t, controller.Router().SetCircuitBreaker("Idle", breaker))
+	assert.Same(t, breaker, controller.Router().CircuitBreaker("Idle"))
+
+	result, err := controller.Router().Request("Idle", "ok", nil, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result)
+	for i := 0; i < 2; i++ {
+		params := ExecParams{}
+		params.ExpectReturn()
+		svc.Exec("fail", params)
+		params.ReturnSignal().Wait()
+	}
+	assert.Equal(t, CircuitClosed, breaker.State(), "requests sent with Exec must not be recorded")
+	assert.Equal(t, uint64(1), breaker.Stats().Allowed)
+	for i := 0; i < 2; i++ {
+		_, err = controller.Router().Request("Idle", "fail", nil, time.Second)
+		assert.ErrorIs(t, err, errBreakerTest)
+	}
+	assert.True(t, waitCondition(func() bool { return breaker.State() == CircuitOpen }))
+
+	_, err = controller.Router().Request("Idle", "ok", nil, time.Second)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	var openErr *CircuitOpenError
+	assert.ErrorAs(t, err, &openErr)
+	assert.Equal(t, "Idle", openErr.ServiceID)
+	assert.ErrorIs(t, controller.Router().ForwardE("Idle", "ok", nil), ErrCircuitOpen)
+	assert.Equal(t, uint64(2), breaker.Stats().ShortCircuited)
+
+	assert.NoError(t, controller.Router().SetCircuitBreaker("Idle", nil))
+	assert.Nil(t, controller.Router().CircuitBreaker("Idle"))
+	result, err = controller.Router().Request("Idle", "ok", nil, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result)
+}
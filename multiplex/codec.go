@@ -0,0 +1,90 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec serializes values, including ExecParams, for storage or transmission.
+//
+// Available since v0.11.0
+type Codec interface {
+	// Return the name of the Codec.
+	Name() string
+	// Encode v into bytes.
+	Marshal(v interface{}) ([]byte, error)
+	// Decode data into the value pointed by v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec implements Codec using encoding/json.
+// Numbers in ExecParams are decoded as float64.
+//
+// Available since v0.11.0
+type JSONCodec struct{}
+
+// Return the name of the Codec.
+//
+// Available since v0.11.0
+func (c JSONCodec) Name() string {
+	return "json"
+}
+
+// Encode v into JSON.
+//
+// Available since v0.11.0
+func (c JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode JSON data into the value pointed by v.
+//
+// Available since v0.11.0
+func (c JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// GobCodec implements Codec using encoding/gob.
+// Custom types stored in ExecParams must be registered with gob.Register.
+//
+// Available since v0.11.0
+type GobCodec struct{}
+
+// Return the name of the Codec.
+//
+// Available since v0.11.0
+func (c GobCodec) Name() string {
+	return "gob"
+}
+
+// Encode v into gob.
+//
+// Available since v0.11.0
+func (c GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode gob data into the value pointed by v.
+//
+// Available since v0.11.0
+func (c GobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Return a copy of params without entries that are only meaningful within the process.
+func serializableParams(params ExecParams) ExecParams {
+	clone := params.Clone()
+	clone.Delete("return")
+	return clone
+}
@@ -6,14 +6,30 @@
 
 package multiplex
 
-import "github.com/tforce-io/tf-golib/diag"
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/tforce-io/tf-golib/diag"
+)
 
 // ServiceController is a controller for managing services and routing messages between them.
 //
 // Available since v0.5.0
 type ServiceController struct {
 	ServiceCore
-	services map[string]Service
+	services   map[string]Service
+	servicesMu sync.RWMutex
+	fallback   string
+	topics     *topicRegistry
+	scheduler  *scheduler
+
+	dependencies     map[string][]string
+	lifecycleMu      sync.Mutex
+	lifecycleStarted bool
+	started          []LifecycleService
+	supervisor       *supervisor
+	journal          atomic.Value
 }
 
 // Return new ServiceRouter.
@@ -22,10 +38,12 @@ type ServiceController struct {
 func NewServiceController(logger diag.Logger) *ServiceController {
 	svc := &ServiceController{}
 	svc.InitServiceCore("Controller", logger, svc.coreProcessHook)
-	svc.i.Router = &ServiceRouter{
-		c: svc,
-	}
+	svc.i.Router = newServiceRouter(svc)
 	svc.services = make(map[string]Service)
+	svc.topics = newTopicRegistry()
+	svc.scheduler = newScheduler()
+	svc.dependencies = make(map[string][]string)
+	svc.supervisor = newSupervisor()
 	return svc
 }
 
@@ -37,65 +55,224 @@ func (s *ServiceController) Register(service Service) bool {
 		s.i.Logger.Warn("Service %s's router is invalid", service.ServiceID())
 		return false
 	}
+	s.servicesMu.Lock()
+	defer s.servicesMu.Unlock()
 	s.services[service.ServiceID()] = service
 	return true
 }
 
-// Unregister a service by serviceID.
+// Unregister a service by serviceID. The service is also unsubscribed from all topics,
+// is no longer supervised and all schedules delivering to it are canceled.
 //
 // Available since v0.5.0
 func (s *ServiceController) Unregister(serviceID string) {
+	s.servicesMu.Lock()
+	service, found := s.services[serviceID]
 	delete(s.services, serviceID)
+	s.servicesMu.Unlock()
+	s.unsupervise(serviceID, service, found)
+	s.UnsubscribeAll(serviceID)
+	s.scheduler.cancelService(serviceID)
 }
 
-// Run the controller.
+// Return the registered service by serviceID.
+//
+// Available since v0.11.0
+func (s *ServiceController) Service(serviceID string) (Service, bool) {
+	s.servicesMu.RLock()
+	defer s.servicesMu.RUnlock()
+	service, found := s.services[serviceID]
+	return service, found
+}
+
+// Set the service receiving messages sent to unknown services, also known as dead-letter service.
+// The original target is stored in params under OriginalServiceIDKey.
+// Empty serviceID disables the fallback.
+//
+// Available since v0.11.0
+func (s *ServiceController) SetFallback(serviceID string) {
+	s.servicesMu.Lock()
+	defer s.servicesMu.Unlock()
+	s.fallback = serviceID
+}
+
+// Return the service receiving messages sent to unknown services.
+//
+// Available since v0.11.0
+func (s *ServiceController) Fallback() string {
+	s.servicesMu.RLock()
+	defer s.servicesMu.RUnlock()
+	return s.fallback
+}
+
+// Run the controller. Registered LifecycleService are started in dependency order first,
+// the controller won't run if any of them fails to start.
+// In background mode, the services are stopped after the controller exited.
+// Startup errors are only logged, use RunE to receive them.
 //
 // Available since v0.5.0
 func (s *ServiceController) Run(background bool) {
-	s.SetWorker(1)
+	if err := s.RunE(background); err != nil {
+		s.i.Logger.Errorf(err, "Controller failed to start services.")
+	}
+}
 
+// Run the controller like Run and return the error preventing registered LifecycleService from starting.
+// In background mode, the error of stopping services is returned after the controller exited.
+//
+// Available since v0.11.0
+func (s *ServiceController) RunE(background bool) error {
+	if err := s.Start(); err != nil {
+		return err
+	}
 	if background {
 		s.i.Background = true
+		atomic.StoreUint32(&s.i.background, 1)
+	}
+	s.SetWorker(1)
+
+	if background {
 		<-s.i.ExitChan
+		atomic.StoreUint32(&s.i.background, 0)
 		s.i.Background = false
+		return s.Stop()
 	}
+	return nil
 }
 
 // coreProcessHook is responsible for processing messages in the controller.
 //
 // Available since v0.5.0
 func (s *ServiceController) coreProcessHook(workerID uint64, msg *ServiceMessage) *HookState {
-	if msg.Extra == nil && msg.Command == "exit" {
+	extra, ok := msg.Extra.(*ControllerExtra)
+	if !ok {
 		return &HookState{Handled: false}
 	}
-	serviceID := msg.Extra.(*ControllerExtra).ServiceID
-	s.services[serviceID].Exec(msg.Command, msg.Params)
+	service, found := s.Service(extra.ServiceID)
+	if !found {
+		return s.deadLetter(extra.ServiceID, msg)
+	}
+	service.Exec(msg.Command, msg.Params)
 	return &HookState{Handled: true}
 }
 
+// Route the message for an unknown service to the fallback service if any,
+// otherwise report NoSuchServiceError to the sender.
+func (s *ServiceController) deadLetter(serviceID string, msg *ServiceMessage) *HookState {
+	err := &NoSuchServiceError{ServiceID: serviceID}
+	if fallback, found := s.Service(s.Fallback()); found {
+		s.i.Logger.Warnf("Service %s not found, command %q routed to %s.", serviceID, msg.Command, fallback.ServiceID())
+		params := msg.Params.Clone()
+		if breaker := takeCircuit(params); breaker != nil {
+			breaker.Record(err)
+		}
+		params[OriginalServiceIDKey] = serviceID
+		fallback.Exec(msg.Command, params)
+		return &HookState{Handled: true}
+	}
+	s.i.Logger.Warnf("Service %s not found, command %q dropped.", serviceID, msg.Command)
+	msg.ReturnError(err)
+	return &HookState{Handled: true, Error: err}
+}
+
 // ServiceRouter is responsible for routing messages between services.
 //
 // Available since v0.5.0
 type ServiceRouter struct {
+	// lastRequestID must go first to guarantee alignment for atomic operations.
+	lastRequestID uint64
+
 	c *ServiceController
+
+	pendingMu sync.Mutex
+	pending   map[string]*pendingRequest
+
+	chainMu      sync.Mutex
+	interceptors []ForwardInterceptor
+	chain        atomic.Value
+
+	breakersMu sync.RWMutex
+	breakers   map[string]*CircuitBreaker
+}
+
+// Return new ServiceRouter for the controller.
+func newServiceRouter(c *ServiceController) *ServiceRouter {
+	return &ServiceRouter{
+		c:        c,
+		pending:  make(map[string]*pendingRequest),
+		breakers: make(map[string]*CircuitBreaker),
+	}
 }
 
 // Forward the message to the specified serviceID.
+// The message passes through forwarding interceptors, errors are logged, use ForwardE to receive them.
 //
 // Available since v0.5.0
 func (s *ServiceRouter) Forward(serviceID, command string, params ExecParams) {
+	if err := s.ForwardE(serviceID, command, params); err != nil {
+		s.c.i.Logger.Errorf(err, "Router: Forward command %q to %s failed.", command, serviceID)
+	}
+}
+
+// Forward the message to the specified serviceID and return the error preventing its delivery.
+// The message passes through forwarding interceptors, which may reject it with an error.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) ForwardE(serviceID, command string, params ExecParams) error {
+	return s.forward("", serviceID, command, params)
+}
+
+// Forward the message sent by source service to the specified serviceID, recording it in the Journal if any.
+func (s *ServiceRouter) forward(source, serviceID, command string, params ExecParams) error {
 	msg := &ServiceMessage{
 		Command: command,
 		Params:  params,
+		source:  source,
+	}
+	return s.journaled(serviceID, msg, func() error {
+		return s.guardedForward(serviceID, msg)
+	})
+}
+
+// Call deliver to hand the message over to serviceID, recording it and the error returned in the Journal if any.
+func (s *ServiceRouter) journaled(serviceID string, msg *ServiceMessage, deliver func() error) error {
+	journal := s.c.Journal()
+	if journal == nil {
+		return deliver()
+	}
+	report := func(err error) {
+		s.c.i.Logger.Errorf(err, "Router: Command %q to %s can't be journaled.", msg.Command, serviceID)
 	}
+	entry := journal.begin(serviceID, msg, report)
+	err := deliver()
+	if journalErr := journal.forwarded(entry, err); journalErr != nil {
+		report(journalErr)
+	}
+	return err
+}
+
+// Enqueue the message to the controller for delivery.
+// NoSuchServiceError is returned if the service is unknown and there is no fallback service.
+func (s *ServiceRouter) enqueue(serviceID string, msg *ServiceMessage) error {
 	if serviceID != "" {
+		if _, found := s.c.Service(serviceID); !found {
+			if _, found := s.c.Service(s.c.Fallback()); !found {
+				return &NoSuchServiceError{ServiceID: serviceID}
+			}
+		}
 		msg.Extra = &ControllerExtra{
 			ServiceID: serviceID,
 		}
 	}
-	s.c.i.MainChan <- msg
+	s.c.i.push(msg)
+	return nil
 }
 
+// Parameter key holding the original target of a message routed to the fallback service.
+//
+// Available since v0.11.0
+const OriginalServiceIDKey = "original_service_id"
+
 // ControllerExtra contains additional information for request to the controller.
 //
 // Available since v0.5.0
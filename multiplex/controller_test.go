@@ -7,6 +7,9 @@
 package multiplex
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -97,3 +100,85 @@ func TestServiceController_Integration(t *testing.T) {
 	assert.Contains(t, logger2.LastMessage(), "INFO Hash#1: Value hashed: ", "invalid message")
 	assert.Contains(t, logger3.LastMessage(), "INFO Random#1: Value randomed:", "invalid message")
 }
+
+func TestServiceController_UnknownService(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewServiceController(logger)
+	svc.Run(false)
+	err := svc.Router().ForwardE("Missing", "", ExecParams{})
+	assert.True(t, errors.Is(err, ErrNoSuchService), "unknown service must be reported")
+	var noSuchErr *NoSuchServiceError
+	assert.True(t, errors.As(err, &noSuchErr))
+	assert.Equal(t, "Missing", noSuchErr.ServiceID)
+	result, err := svc.Router().Request("Missing", "", nil, time.Second)
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, ErrNoSuchService), "request must fail immediately")
+	svc.Dispatch("Missing", "ping", nil)
+	assert.Equal(t, `ERROR no such service "Missing" Controller: Dispatch command "ping" to Missing failed.`, logger.LastMessage())
+}
+
+func TestServiceController_UnregisteredInFlight(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewServiceController(logger)
+	idle := NewIdleService(logger)
+	idle.SetRouter(svc)
+	svc.Register(idle)
+	params := ExecParams{}
+	params.ExpectReturn()
+	err := svc.Router().ForwardE("Idle", "", params)
+	assert.NoError(t, err)
+	svc.Unregister("Idle")
+	svc.Run(false)
+	assert.Nil(t, params.WaitForReturn())
+	assert.True(t, errors.Is(params.ReturnErr(), ErrNoSuchService), "sender must be notified")
+	assert.Contains(t, logger.AllMessages(), `WARN Service Idle not found, command "" dropped.`)
+}
+
+func TestServiceController_Fallback(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewServiceController(logger)
+	deadLetter := NewCollectorService("DeadLetter", logger)
+	deadLetter.SetRouter(svc)
+	deadLetter.SetWorker(1)
+	svc.Register(deadLetter)
+	svc.SetFallback("DeadLetter")
+	assert.Equal(t, "DeadLetter", svc.Fallback())
+	svc.Run(false)
+	err := svc.Router().ForwardE("Missing", "ping", ExecParams{"key": "value"})
+	assert.NoError(t, err, "message must be accepted by fallback")
+	msg := deadLetter.Receive(t)
+	assert.Equal(t, "ping", msg.Command)
+	assert.Equal(t, "Missing", msg.GetParam(OriginalServiceIDKey, ""))
+	assert.Equal(t, "value", msg.GetParam("key", ""))
+	svc.SetFallback("")
+	err = svc.Router().ForwardE("Missing", "ping", nil)
+	assert.True(t, errors.Is(err, ErrNoSuchService))
+}
+
+func TestServiceController_ConcurrentRegister(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewServiceController(logger)
+	svc.Run(false)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		serviceID := fmt.Sprintf("Idle%d", i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				idle := NewIdleService(logger)
+				idle.i.ServiceID = serviceID
+				idle.SetRouter(svc)
+				svc.Register(idle)
+				svc.Unregister(serviceID)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				svc.Router().Forward(serviceID, "", nil)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -7,6 +7,12 @@
 package multiplex
 
 import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tforce-io/tf-golib/async"
 	"github.com/tforce-io/tf-golib/diag"
 )
 
@@ -64,6 +70,13 @@ type ServiceCore struct {
 //
 // Available since v0.5.0
 type ServiceCoreInternal struct {
+	// Number of requests enqueued but not processed yet.
+	unprocessed int64
+	// Whether requests are processed inline by Exec.
+	inline uint32
+	// Whether Process routines report their exit to ExitChan.
+	background uint32
+
 	ServiceID string
 	WorkerID  uint64
 	Router    *ServiceRouter
@@ -75,9 +88,34 @@ type ServiceCoreInternal struct {
 	WorkerCounter *Uint64ThreadSafe
 	WorkerCount   uint64
 
+	// Moving average of time spent by CoreProcessHook, in nanoseconds.
+	Latency *diag.Gauge
+
 	Logger diag.Logger
 
 	CoreProcessHook func(workerID uint64, msg *ServiceMessage) *HookState
+
+	workerMu      sync.Mutex
+	workerSignal  sync.Mutex
+	workerChanged chan struct{}
+	// Number of Process routines spawned but not running yet, guarded by workerSignal.
+	workerStarting uint64
+
+	chainMu      sync.Mutex
+	interceptors []Interceptor
+	chain        atomic.Value
+
+	// Function handling crashed Process routines, as func(workerID uint64, err error).
+	crashHandler atomic.Value
+
+	affinity *affinity
+	batch    atomic.Value
+	dedup    atomic.Value
+	// Persistent storage of pending requests, as *DurableQueue.
+	durable atomic.Value
+
+	// Last error reported by processing, as *errorRecord.
+	lastError atomic.Value
 }
 
 // Init ServiceCore internal and return the reference for later access.
@@ -92,10 +130,13 @@ func (s *ServiceCore) InitServiceCore(serviceID string, logger diag.Logger, proc
 		MainChan:      make(chan *ServiceMessage, MainChainCapacity),
 		ExitChan:      make(chan bool, ExtraChanCapacity),
 		WorkerCounter: &Uint64ThreadSafe{},
+		Latency:       diag.NewGauge(0),
 
 		Logger: logger,
 
 		CoreProcessHook: processHook,
+
+		workerChanged: make(chan struct{}),
 	}
 	return s.i
 }
@@ -122,80 +163,442 @@ func (s ServiceCore) SetRouter(controller *ServiceController) {
 }
 
 // Set number of Process routines the service should use to handle requests.
+// Calling SetWorker while a previous resize is still in progress is allowed,
+// the service will converge to the latest requested number.
+// Use WaitWorker to wait until the resize has actually completed.
 //
 // Available since v0.5.0
 func (s ServiceCore) SetWorker(workerCount uint64) {
-	s.i.WorkerCounter.Lock()
-	defer s.i.WorkerCounter.Unlock()
-	if s.i.WorkerCounter.ValueNoLock() != s.i.WorkerCount {
-		return
-	}
-	if workerCount > s.i.WorkerCounter.ValueNoLock() {
-		for i := s.i.WorkerCounter.ValueNoLock(); i < workerCount; i++ {
-			s.i.WorkerID++
-			go s.process(s.i.WorkerID)
+	s.i.workerMu.Lock()
+	defer s.i.workerMu.Unlock()
+	if workerCount > s.i.WorkerCount {
+		for i := s.i.WorkerCount; i < workerCount; i++ {
+			s.spawn()
 		}
-		s.i.WorkerCount = workerCount
 	}
-	if workerCount < s.i.WorkerCounter.ValueNoLock() {
-		for i := s.i.WorkerCounter.ValueNoLock(); i > workerCount; i-- {
+	if workerCount < s.i.WorkerCount {
+		for i := s.i.WorkerCount; i > workerCount; i-- {
 			cmd := &ServiceMessage{
 				Command: "exit",
 			}
 			s.i.MainChan <- cmd
 		}
-		s.i.WorkerCount = workerCount
 	}
+	s.i.WorkerCount = workerCount
 }
 
 // Return number of Process routines the service is managing.
 //
 // Available since v0.5.0
 func (s ServiceCore) WorkerCount() uint64 {
+	s.i.workerMu.Lock()
+	defer s.i.workerMu.Unlock()
 	return s.i.WorkerCount
 }
 
+// Wait until number of running Process routines matches the number requested by SetWorker
+// and all Process routines started by SetWorker are running.
+// Return false if timeout elapsed before that. Non-positive timeout means waiting indefinitely.
+//
+// Available since v0.11.0
+func (s ServiceCore) WaitWorker(timeout time.Duration) bool {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for {
+		desired := s.WorkerCount()
+		s.i.workerSignal.Lock()
+		actual := s.i.WorkerCounter.Value()
+		starting := s.i.workerStarting
+		changed := s.i.workerChanged
+		s.i.workerSignal.Unlock()
+		if actual == desired && starting == 0 {
+			return true
+		}
+		select {
+		case <-changed:
+		case <-expired:
+			return false
+		}
+	}
+}
+
+// Return number of pending requests in the queue.
+//
+// Available since v0.11.0
+func (s ServiceCore) QueueLength() int {
+	if s.i.affinity != nil {
+		return len(s.i.MainChan) + s.i.affinity.queued()
+	}
+	return len(s.i.MainChan)
+}
+
+// Return moving average of time spent to process a request.
+//
+// Available since v0.11.0
+func (s ServiceCore) Latency() time.Duration {
+	return time.Duration(s.i.Latency.Value())
+}
+
+// Use the DurableQueue to persist requests until they are processed successfully.
+// Pending requests of the queue are enqueued again in a separate routine.
+// Params must be serializable by the queue's Codec, otherwise requests are kept in memory only.
+//
+// Available since v0.11.0
+func (s ServiceCore) SetDurableQueue(queue *DurableQueue) {
+	s.i.durable.Store(queue)
+	pending := queue.Pending()
+	if len(pending) == 0 {
+		return
+	}
+	s.i.Logger.Infof("%s: Restoring %d pending requests.", s.i.ServiceID, len(pending))
+	go func() {
+		for _, msg := range pending {
+			s.i.push(msg)
+		}
+	}()
+}
+
 // Enqueue the request.
+// Duplicate requests are dropped if deduplication is enabled.
+// The request is persisted first if the service uses a DurableQueue.
 //
 // Available since v0.5.0
 func (s ServiceCore) Exec(command string, params ExecParams) {
 	msg := &ServiceMessage{
 		Command: command,
 		Params:  params,
+		circuit: takeCircuit(params),
+	}
+	if s.i.deduplicate(msg) {
+		if msg.circuit != nil {
+			// The outcome is recorded when the original request is processed.
+			msg.circuit.Record(nil)
+		}
+		return
+	}
+	if queue := s.i.durableQueue(); queue != nil && command != "exit" {
+		seq, err := queue.Append(command, params)
+		if err != nil {
+			s.i.Logger.Errorf(err, "%s: Command %q can't be persisted.", s.i.ServiceID, command)
+		}
+		msg.seq = seq
+	}
+	if atomic.LoadUint32(&s.i.inline) == 1 {
+		s.processInline(msg)
+		return
+	}
+	s.i.push(msg)
+}
+
+// Return number of requests enqueued but not processed yet, including requests being processed.
+//
+// Available since v0.11.0
+func (s ServiceCore) Unprocessed() int {
+	return int(atomic.LoadInt64(&s.i.unprocessed))
+}
+
+// Process requests synchronously in the routine calling Exec instead of Process routines.
+// Panics raised while processing are propagated to the caller.
+// This is intended for tests, see package multiplextest.
+//
+// Available since v0.11.0
+func (s ServiceCore) SetInline(inline bool) {
+	if inline {
+		atomic.StoreUint32(&s.i.inline, 1)
+	} else {
+		atomic.StoreUint32(&s.i.inline, 0)
 	}
-	s.i.MainChan <- msg
 }
 
 // Request other service to handle the request via configurated Router.
+// Routing errors are reported to the Logger.
 //
 // Available since v0.5.0
 func (s ServiceCore) Dispatch(serviceID string, command string, params ExecParams) {
-	s.i.Router.Forward(serviceID, command, params)
+	if err := s.i.Router.forward(s.i.ServiceID, serviceID, command, params); err != nil {
+		s.i.Logger.Errorf(err, "%s: Dispatch command %q to %s failed.", s.i.ServiceID, command, serviceID)
+	}
+}
+
+// Request other service to handle the request via configurated Router and wait for its result.
+// RequestTimeoutError is returned if the service doesn't return within timeout.
+//
+// Available since v0.11.0
+func (s ServiceCore) Request(serviceID string, command string, params ExecParams, timeout time.Duration) (interface{}, error) {
+	return s.i.Router.request(s.i.ServiceID, serviceID, command, params, timeout)
+}
+
+// Request other service to handle the request via configurated Router and return a Future of its result.
+// RequestTimeoutError is delivered if the service doesn't return within timeout.
+//
+// Available since v0.11.0
+func (s ServiceCore) RequestFuture(serviceID string, command string, params ExecParams, timeout time.Duration) *async.Future[interface{}] {
+	return s.i.Router.requestFuture(s.i.ServiceID, serviceID, command, params, timeout)
+}
+
+// Request other service to handle the request via configurated Router without waiting.
+// The result will be delivered back to this service as a message with ReplyCommand.
+// Return the correlation ID of the request.
+//
+// Available since v0.11.0
+func (s ServiceCore) RequestAsync(serviceID string, command string, params ExecParams, timeout time.Duration) string {
+	return s.i.Router.RequestAsync(s.i.ServiceID, serviceID, command, params, timeout)
+}
+
+// Start a new Process routine. Must be called with workerMu held.
+func (s ServiceCore) spawn() {
+	s.i.WorkerID++
+	s.i.startWorker()
+	var shard *affinityShard
+	if s.i.affinity != nil {
+		shard = s.i.affinity.attach(s.i.WorkerID)
+	}
+	go s.process(s.i.WorkerID, shard)
 }
 
 // Process routine to handle the request.
 //
 // Available since v0.5.0
-func (s ServiceCore) process(workerID uint64) {
-	s.i.WorkerCounter.Add(1)
+func (s ServiceCore) process(workerID uint64, shard *affinityShard) {
 	s.i.Logger.Infof("%s#%d: Process started.", s.i.ServiceID, workerID)
+	s.i.addWorker(1)
+	ctx := &ProcessContext{
+		ServiceID: s.i.ServiceID,
+		WorkerID:  workerID,
+	}
+	err := s.run(ctx, shard)
+	if shard != nil {
+		s.i.affinity.detach(shard)
+	}
+	if err != nil {
+		s.i.addWorker(-1)
+		s.i.Logger.Errorf(err, "%s#%d: Process crashed.", s.i.ServiceID, workerID)
+		if handler := s.i.crashHandlerFunc(); handler != nil {
+			handler(workerID, err)
+		}
+		return
+	}
+	s.i.addWorker(-1)
+	s.i.Logger.Infof("%s#%d: Process exited.", s.i.ServiceID, workerID)
+	if atomic.LoadUint32(&s.i.background) == 1 {
+		s.i.ExitChan <- true
+	}
+}
+
+// Handle requests until receiving exit request.
+// If the service is supervised, PanicError is returned if processing a request panicked,
+// the sender will receive the same error. Otherwise the panic is propagated.
+func (s ServiceCore) run(ctx *ProcessContext, shard *affinityShard) (err error) {
+	var msg *ServiceMessage
+	defer func() {
+		if !s.i.supervised() {
+			return
+		}
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+			if msg != nil {
+				msg.ReturnError(err)
+				s.i.observeOutcome(msg, &HookState{Handled: true, Error: err})
+				s.i.processed(msg)
+			}
+		}
+	}()
+	var next *ServiceMessage
 	status := InitState
 	for status != ExitState {
-		msg := <-s.i.MainChan
-		if s.i.CoreProcessHook != nil {
-			hookState := s.i.CoreProcessHook(workerID, msg)
-			if hookState.Handled {
-				continue
+		msg, next = next, nil
+		if msg == nil {
+			msg = s.i.receive(shard)
+		}
+		if batch := s.i.batchConfig(); batch != nil && msg.Command != "exit" {
+			if next, err = s.processBatch(ctx, shard, batch, msg); err != nil {
+				return err
 			}
+			continue
+		}
+		process := s.i.processFunc()
+		if msg.Command == "exit" {
+			// Interceptors must not be able to reject the exit request and keep the routine running.
+			process = s.i.baseProcess
+		}
+		start := time.Now()
+		hookState := process(ctx, msg)
+		s.i.observeLatency(time.Since(start))
+		s.i.acknowledge(msg, hookState)
+		s.i.observeOutcome(msg, hookState)
+		s.i.processed(msg)
+		if shard != nil {
+			s.i.affinity.done(shard)
+		}
+		if hookState != nil && hookState.Handled {
+			continue
 		}
 		if msg.Command == "exit" {
 			status = ExitState
 			continue
 		}
 	}
-	s.i.WorkerCounter.Sub(1)
-	s.i.Logger.Infof("%s#%d: Process exited.", s.i.ServiceID, workerID)
-	if s.i.Background {
-		s.i.ExitChan <- true
+	return nil
+}
+
+// Process the request in the calling routine.
+func (s ServiceCore) processInline(msg *ServiceMessage) {
+	if msg.Command == "exit" {
+		return
+	}
+	ctx := &ProcessContext{
+		ServiceID: s.i.ServiceID,
+	}
+	if batch := s.i.batchConfig(); batch != nil {
+		single := *batch
+		single.size = 1
+		if _, err := s.processBatch(ctx, nil, &single, msg); err != nil {
+			panic(err)
+		}
+		return
+	}
+	start := time.Now()
+	hookState := s.i.processFunc()(ctx, msg)
+	s.i.observeLatency(time.Since(start))
+	s.i.acknowledge(msg, hookState)
+	s.i.observeOutcome(msg, hookState)
+}
+
+// Start a new Process routine replacing a crashed one.
+// Return false if the service already has enough Process routines.
+func (s ServiceCore) respawn() bool {
+	s.i.workerMu.Lock()
+	defer s.i.workerMu.Unlock()
+	if s.i.workers() >= s.i.WorkerCount {
+		return false
+	}
+	s.spawn()
+	return true
+}
+
+// Return the ServiceCore for access within the package.
+func (s ServiceCore) serviceCore() ServiceCore {
+	return s
+}
+
+// Enqueue the request and count it as unprocessed.
+func (i *ServiceCoreInternal) push(msg *ServiceMessage) {
+	msg.tracked = true
+	atomic.AddInt64(&i.unprocessed, 1)
+	i.MainChan <- msg
+}
+
+// Mark the request as processed.
+func (i *ServiceCoreInternal) processed(msg *ServiceMessage) {
+	if msg.tracked {
+		msg.tracked = false
+		atomic.AddInt64(&i.unprocessed, -1)
+	}
+}
+
+// Return the function handling crashed Process routines, nil if the service is not supervised.
+func (i *ServiceCoreInternal) crashHandlerFunc() func(workerID uint64, err error) {
+	handler, _ := i.crashHandler.Load().(func(workerID uint64, err error))
+	return handler
+}
+
+// Return whether crashed Process routines are handled by a supervisor.
+func (i *ServiceCoreInternal) supervised() bool {
+	return i.crashHandlerFunc() != nil
+}
+
+// Record a Process routine spawned but not running yet.
+func (i *ServiceCoreInternal) startWorker() {
+	i.workerSignal.Lock()
+	defer i.workerSignal.Unlock()
+	i.workerStarting++
+}
+
+// Return number of Process routines running or spawned but not running yet.
+func (i *ServiceCoreInternal) workers() uint64 {
+	i.workerSignal.Lock()
+	defer i.workerSignal.Unlock()
+	return i.WorkerCounter.Value() + i.workerStarting
+}
+
+// Update number of running Process routines and wake up routines waiting for it.
+// A positive delta means Process routines spawned by startWorker are now running.
+func (i *ServiceCoreInternal) addWorker(delta int) {
+	i.workerSignal.Lock()
+	defer i.workerSignal.Unlock()
+	if delta > 0 {
+		i.workerStarting -= uint64(delta)
+		i.WorkerCounter.Add(uint64(delta))
+	} else {
+		i.WorkerCounter.Sub(uint64(-delta))
+	}
+	close(i.workerChanged)
+	i.workerChanged = make(chan struct{})
+}
+
+// Return the next request for the Process routine.
+func (i *ServiceCoreInternal) receive(shard *affinityShard) *ServiceMessage {
+	if shard == nil {
+		return <-i.MainChan
+	}
+	return i.affinity.receive(shard)
+}
+
+// Return the DurableQueue persisting requests, nil if there is none.
+func (i *ServiceCoreInternal) durableQueue() *DurableQueue {
+	queue, _ := i.durable.Load().(*DurableQueue)
+	return queue
+}
+
+// Acknowledge the persisted request if it was processed successfully.
+func (i *ServiceCoreInternal) acknowledge(msg *ServiceMessage, state *HookState) {
+	queue := i.durableQueue()
+	if msg.seq == 0 || queue == nil {
+		return
+	}
+	if state != nil && state.Error != nil {
+		i.Logger.Warnf("%s: Command %q failed, it will be delivered again on restart.", i.ServiceID, msg.Command)
+		return
+	}
+	if err := queue.Ack(msg.seq); err != nil {
+		i.Logger.Errorf(err, "%s: Command %q can't be acknowledged.", i.ServiceID, msg.Command)
+	}
+}
+
+// Record the outcome of a processed request as last error, for deduplication and into the CircuitBreaker which admitted it if any.
+func (i *ServiceCoreInternal) observeOutcome(msg *ServiceMessage, state *HookState) {
+	if msg.Command == "exit" {
+		return
+	}
+	var err error
+	if state != nil {
+		err = state.Error
+	}
+	if msg.dedup != nil {
+		msg.dedup.complete(nil, err)
+	}
+	if err != nil {
+		i.lastError.Store(&errorRecord{err: err, time: time.Now()})
+	}
+	if msg.circuit != nil {
+		msg.circuit.Record(err)
+	}
+}
+
+// Record processing duration of a request into the moving average.
+func (i *ServiceCoreInternal) observeLatency(duration time.Duration) {
+	const weight = 0.2
+	prev := i.Latency.Value()
+	if prev == 0 {
+		i.Latency.Set(float64(duration))
+		return
 	}
+	i.Latency.Set(prev*(1-weight) + float64(duration)*weight)
 }
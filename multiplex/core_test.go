@@ -9,6 +9,7 @@ package multiplex
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"strings"
 	"testing"
 	"time"
 
@@ -43,6 +44,44 @@ func TestServiceCore_Worker(t *testing.T) {
 	assert.Equal(t, uint64(10), svc.WorkerCount(), "mismatch worker count")
 }
 
+func TestServiceCore_WaitWorker(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewIdleService(logger)
+	svc.SetWorker(4)
+	svc.SetWorker(1)
+	svc.SetWorker(3)
+	assert.True(t, svc.WaitWorker(time.Second), "resize must be completed")
+	assert.Equal(t, uint64(3), svc.i.WorkerCounter.Value(), "mismatch running worker count")
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(0), "resize must be completed")
+	assert.Equal(t, uint64(0), svc.i.WorkerCounter.Value(), "mismatch running worker count")
+}
+
+func TestServiceCore_WaitWorker_Started(t *testing.T) {
+	logger := diag.NewDebugLogger(20)
+	svc := NewIdleService(logger)
+	svc.SetWorker(8)
+	assert.True(t, svc.WaitWorker(time.Second), "resize must be completed")
+	started := 0
+	for _, message := range logger.AllMessages() {
+		if strings.Contains(message, "Process started.") {
+			started++
+		}
+	}
+	assert.Equal(t, 8, started, "WaitWorker must not return before Process routines are running")
+	svc.SetWorker(2)
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second), "resize must be completed")
+	assert.Equal(t, 0, svc.QueueLength(), "exit requests must be consumed")
+}
+
+func TestServiceCore_WaitWorker_Timeout(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewEchoService(logger)
+	svc.i.WorkerCount = 1
+	assert.False(t, svc.WaitWorker(10*time.Millisecond), "resize must not be completed")
+}
+
 func TestServiceCore_Exec(t *testing.T) {
 	logger := diag.NewDebugLogger(10)
 	svc := NewEchoService(logger)
@@ -54,6 +93,30 @@ func TestServiceCore_Exec(t *testing.T) {
 	assert.Equal(t, "INFO Echo#1: Message received: Hello, World!", logger.LastMessage(), "invalid message")
 }
 
+func TestServiceCore_SetInline(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewEchoService(logger)
+	svc.SetInline(true)
+	svc.Exec("", ExecParams{
+		"message": "Inline",
+	})
+	assert.Equal(t, "INFO Echo#0: Message received: Inline", logger.LastMessage(), "message must be processed inline")
+	assert.Equal(t, 0, svc.Unprocessed())
+}
+
+func TestServiceCore_Unprocessed(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewEchoService(logger)
+	for i := 0; i < 3; i++ {
+		svc.Exec("", ExecParams{
+			"message": "Queued",
+		})
+	}
+	assert.Equal(t, 3, svc.Unprocessed())
+	svc.SetWorker(1)
+	assert.Eventually(t, func() bool { return svc.Unprocessed() == 0 }, time.Second, time.Millisecond)
+}
+
 type EchoService struct {
 	ServiceCore
 	i *ServiceCoreInternal
@@ -138,3 +201,18 @@ func (s *ShutdownService) coreProcessHook(workerID uint64, msg *ServiceMessage)
 	s.Dispatch("", "exit", ExecParams{})
 	return &HookState{Handled: true}
 }
+
+type IdleService struct {
+	ServiceCore
+	i *ServiceCoreInternal
+}
+
+func NewIdleService(logger diag.Logger) *IdleService {
+	svc := &IdleService{}
+	svc.i = svc.InitServiceCore("Idle", logger, svc.coreProcessHook)
+	return svc
+}
+
+func (s *IdleService) coreProcessHook(workerID uint64, msg *ServiceMessage) *HookState {
+	return &HookState{Handled: msg.Command != "exit"}
+}
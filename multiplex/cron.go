@@ -0,0 +1,186 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCron is returned when a cron expression is malformed.
+//
+// Available since v0.11.0
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// CronSchedule is a parsed cron expression with 5 fields: minute, hour, day of month, month and day of week.
+// Each field supports "*", single value "5", range "1-5", step "*/15" or "1-30/5" and list "1,15,30".
+// Day of week starts from 0 for Sunday, 7 is also accepted as Sunday.
+// If both day of month and day of week are restricted, a day matches if either field matches.
+// A field starting with "*", such as "*/15", is unrestricted.
+// Macros "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight" and "@hourly" are supported.
+//
+// Available since v0.11.0
+type CronSchedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// Whether day of month or day of week is restricted, in which case a day matches if either field matches.
+	domRestricted bool
+	dowRestricted bool
+}
+
+// cronField defines the bounds of a cron field.
+type cronField struct {
+	name string
+	min  uint
+	max  uint
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse a cron expression.
+//
+// Available since v0.11.0
+func ParseCron(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if macro, ok := cronMacros[spec]; ok {
+		spec = macro
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("%w: expected %d fields, found %d", ErrInvalidCron, len(cronFields), len(fields))
+	}
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		value, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = value
+	}
+	// Sunday can be written as both 0 and 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+		bits[4] &^= 1 << 7
+	}
+	return &CronSchedule{
+		minute:        bits[0],
+		hour:          bits[1],
+		dom:           bits[2],
+		month:         bits[3],
+		dow:           bits[4],
+		domRestricted: !isUnrestrictedCronField(fields[2]),
+		dowRestricted: !isUnrestrictedCronField(fields[4]),
+	}, nil
+}
+
+// Return the first time matching the schedule strictly after t, in the location of t.
+// Zero time is returned if no matching time is found within 5 years.
+//
+// Available since v0.11.0
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Return whether the day of t matches day of month and day of week fields.
+func (c *CronSchedule) matchDay(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domRestricted && c.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Return whether the field starts with "*", in which case it doesn't restrict days even with a step.
+func isUnrestrictedCronField(field string) bool {
+	return strings.HasPrefix(field, "*")
+}
+
+// Parse a cron field into a bit set of allowed values.
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, uint(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			value, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || value == 0 {
+				return 0, fmt.Errorf("%w: invalid step %q in %s field", ErrInvalidCron, part, bounds.name)
+			}
+			rangePart, step = part[:i], uint(value)
+		}
+		low, high := bounds.min, bounds.max
+		if rangePart != "*" {
+			bound := strings.SplitN(rangePart, "-", 2)
+			value, err := strconv.ParseUint(bound[0], 10, 8)
+			if err != nil {
+				return 0, fmt.Errorf("%w: invalid value %q in %s field", ErrInvalidCron, part, bounds.name)
+			}
+			low, high = uint(value), uint(value)
+			if len(bound) == 2 {
+				value, err = strconv.ParseUint(bound[1], 10, 8)
+				if err != nil {
+					return 0, fmt.Errorf("%w: invalid value %q in %s field", ErrInvalidCron, part, bounds.name)
+				}
+				high = uint(value)
+			} else if step > 1 {
+				high = bounds.max
+			}
+		}
+		if low < bounds.min || high > bounds.max || low > high {
+			return 0, fmt.Errorf("%w: value %q out of range in %s field", ErrInvalidCron, part, bounds.name)
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every 5m",
+	}
+	for _, spec := range specs {
+		t.Run(spec, func(t *testing.T) {
+			_, err := ParseCron(spec)
+			assert.True(t, errors.Is(err, ErrInvalidCron), "spec must be rejected")
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// 2025-01-15 is a Wednesday.
+	from := time.Date(2025, 1, 15, 10, 30, 45, 0, time.UTC)
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2025, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"5 * * * *", time.Date(2025, 1, 15, 11, 5, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)},
+		{"0,30 10 * * *", time.Date(2025, 1, 16, 10, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 20 * 5", time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 */10 * 1", time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			cron, err := ParseCron(tt.spec)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, cron.Next(from))
+		})
+	}
+}
+
+func TestCronSchedule_Next_Impossible(t *testing.T) {
+	cron, err := ParseCron("0 0 31 2 *")
+	assert.NoError(t, err)
+	assert.True(t, cron.Next(time.Now()).IsZero(), "impossible schedule must return zero time")
+}
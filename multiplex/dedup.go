@@ -0,0 +1,292 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Parameter key holding the idempotency key of a message.
+//
+// Available since v0.11.0
+const IdempotencyKey = "idempotency_key"
+
+// Number of keys kept by a MemoryIdempotencyStore created with non-positive capacity.
+//
+// Available since v0.11.0
+const DefaultIdempotencyCapacity = 10000
+
+// IdempotencyRecord is the state of an idempotency key.
+//
+// Available since v0.11.0
+type IdempotencyRecord struct {
+	// Whether the original request completed successfully.
+	Done bool
+	// Result of the original request.
+	Result interface{}
+	// Time the key expires.
+	Expires time.Time
+}
+
+// IdempotencyStore keeps idempotency keys of requests processed by a ServiceCore.
+// Implementations must be safe for concurrent use.
+//
+// Available since v0.11.0
+type IdempotencyStore interface {
+	// Return the record of key and true if it is present and not expired.
+	// Otherwise, create a pending record expiring at expires and return false.
+	Reserve(key string, expires time.Time) (IdempotencyRecord, bool)
+	// Mark the record of key as done with the result of the original request.
+	Complete(key string, result interface{})
+	// Remove the record of key, so the next request with the same key is processed.
+	Release(key string)
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore keeping at most capacity keys.
+// The least recently used key is evicted when the store is full.
+//
+// Available since v0.11.0
+type MemoryIdempotencyStore struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+// memoryIdempotencyItem is an entry of MemoryIdempotencyStore.
+type memoryIdempotencyItem struct {
+	key    string
+	record IdempotencyRecord
+}
+
+// Return new empty MemoryIdempotencyStore. Non-positive capacity means DefaultIdempotencyCapacity.
+//
+// Available since v0.11.0
+func NewMemoryIdempotencyStore(capacity int) *MemoryIdempotencyStore {
+	if capacity <= 0 {
+		capacity = DefaultIdempotencyCapacity
+	}
+	return &MemoryIdempotencyStore{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Return the record of key and true if it is present and not expired.
+// Otherwise, create a pending record expiring at expires and return false.
+//
+// Available since v0.11.0
+func (s *MemoryIdempotencyStore) Reserve(key string, expires time.Time) (IdempotencyRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, found := s.items[key]; found {
+		item := element.Value.(*memoryIdempotencyItem)
+		if s.now().Before(item.record.Expires) {
+			s.order.MoveToFront(element)
+			return item.record, true
+		}
+		s.order.Remove(element)
+		delete(s.items, key)
+	}
+	item := &memoryIdempotencyItem{
+		key:    key,
+		record: IdempotencyRecord{Expires: expires},
+	}
+	s.items[key] = s.order.PushFront(item)
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*memoryIdempotencyItem).key)
+	}
+	return IdempotencyRecord{}, false
+}
+
+// Mark the record of key as done with the result of the original request.
+//
+// Available since v0.11.0
+func (s *MemoryIdempotencyStore) Complete(key string, result interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, found := s.items[key]; found {
+		item := element.Value.(*memoryIdempotencyItem)
+		item.record.Done = true
+		item.record.Result = result
+	}
+}
+
+// Remove the record of key, so the next request with the same key is processed.
+//
+// Available since v0.11.0
+func (s *MemoryIdempotencyStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, found := s.items[key]; found {
+		s.order.Remove(element)
+		delete(s.items, key)
+	}
+}
+
+// Return number of keys in the store, including expired ones not evicted yet.
+//
+// Available since v0.11.0
+func (s *MemoryIdempotencyStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// Deduplicate requests carrying the same IdempotencyKey parameter within window.
+// The first request is processed as usual. A duplicate arriving after it succeeded is not processed,
+// its sender receives the result of the original request instead. A duplicate arriving while the
+// original request is being processed receives the outcome once it is available.
+// If the original request fails, its key is released so the next retry is processed,
+// duplicates waiting for it receive the same error.
+// If the request doesn't return by the end of processing, its result is nil.
+// Requests without IdempotencyKey are never deduplicated.
+// If store is nil, a MemoryIdempotencyStore with DefaultIdempotencyCapacity is used.
+// Non-positive window disables deduplication.
+//
+// Available since v0.11.0
+func (s ServiceCore) SetDeduplication(window time.Duration, store IdempotencyStore) {
+	if window <= 0 {
+		s.i.dedup.Store((*dedup)(nil))
+		return
+	}
+	if store == nil {
+		store = NewMemoryIdempotencyStore(0)
+	}
+	s.i.dedup.Store(&dedup{
+		window:  window,
+		store:   store,
+		waiters: make(map[string][]ExecParams),
+	})
+}
+
+// Return number of duplicate requests not processed.
+//
+// Available since v0.11.0
+func (s ServiceCore) Duplicates() uint64 {
+	if d := s.i.dedupConfig(); d != nil {
+		return atomic.LoadUint64(&d.duplicates)
+	}
+	return 0
+}
+
+// dedup stores deduplication options and requests waiting for their original request.
+type dedup struct {
+	// duplicates must go first to guarantee alignment for atomic operations.
+	duplicates uint64
+
+	window time.Duration
+	store  IdempotencyStore
+
+	mu      sync.Mutex
+	waiters map[string][]ExecParams
+}
+
+// dedupEntry tracks the outcome of an original request.
+type dedupEntry struct {
+	dedup     *dedup
+	key       string
+	completed uint32
+}
+
+// Return deduplication options, nil if deduplication is disabled.
+func (i *ServiceCoreInternal) dedupConfig() *dedup {
+	d, _ := i.dedup.Load().(*dedup)
+	return d
+}
+
+// Return whether msg is a duplicate, in which case its sender will receive the outcome of the original request.
+// Otherwise, msg is tracked as the original request of its key if it has one.
+func (i *ServiceCoreInternal) deduplicate(msg *ServiceMessage) bool {
+	d := i.dedupConfig()
+	if d == nil || msg.Command == "exit" {
+		return false
+	}
+	key := idempotencyKey(msg.Params)
+	if key == "" {
+		return false
+	}
+	d.mu.Lock()
+	record, found := d.store.Reserve(key, time.Now().Add(d.window))
+	if found && !record.Done {
+		d.waiters[key] = append(d.waiters[key], msg.Params)
+	}
+	d.mu.Unlock()
+	if found {
+		atomic.AddUint64(&d.duplicates, 1)
+		i.Logger.Debugf("%s: Command %q with idempotency key %q is a duplicate.", i.ServiceID, msg.Command, key)
+		if record.Done {
+			msg.Return(record.Result)
+		}
+		return true
+	}
+
+	entry := &dedupEntry{dedup: d, key: key}
+	msg.dedup = entry
+	if msg.Params["return"] == nil {
+		// Don't add the return to params owned by the sender.
+		msg.Params = msg.Params.Clone()
+		msg.Params.ExpectReturn()
+	}
+	ret := msg.Params["return"].(*ReturnParams)
+	callback := ret.callback
+	ret.callback = func(result interface{}, err error) {
+		entry.complete(result, err)
+		if callback != nil {
+			callback(result, err)
+		}
+	}
+	return false
+}
+
+// Record the outcome of the original request then deliver it to waiting duplicates. Only the first outcome is kept.
+func (e *dedupEntry) complete(result interface{}, err error) {
+	if !atomic.CompareAndSwapUint32(&e.completed, 0, 1) {
+		return
+	}
+	d := e.dedup
+	d.mu.Lock()
+	if err != nil {
+		d.store.Release(e.key)
+	} else {
+		d.store.Complete(e.key, result)
+	}
+	waiters := d.waiters[e.key]
+	delete(d.waiters, e.key)
+	d.mu.Unlock()
+	for _, params := range waiters {
+		if err != nil {
+			params.ReturnError(err)
+		} else {
+			params.Return(result)
+		}
+	}
+}
+
+// Return the idempotency key of params, or empty string if it has none.
+func idempotencyKey(params ExecParams) string {
+	if params == nil {
+		return ""
+	}
+	switch key := params[IdempotencyKey].(type) {
+	case nil:
+		return ""
+	case string:
+		return key
+	default:
+		return fmt.Sprint(key)
+	}
+}
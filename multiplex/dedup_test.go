@@ -0,0 +1,205 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+var errChargeDeclined = errors.New("declined")
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	now := time.Unix(1000, 0)
+	store := NewMemoryIdempotencyStore(2)
+	store.now = func() time.Time { return now }
+
+	_, found := store.Reserve("a", now.Add(time.Minute))
+	assert.False(t, found)
+	record, found := store.Reserve("a", now.Add(time.Minute))
+	assert.True(t, found)
+	assert.False(t, record.Done)
+
+	store.Complete("a", 42)
+	record, found = store.Reserve("a", now.Add(time.Minute))
+	assert.True(t, found)
+	assert.True(t, record.Done)
+	assert.Equal(t, 42, record.Result)
+
+	store.Reserve("b", now.Add(time.Minute))
+	store.Reserve("a", now.Add(time.Minute))
+	store.Reserve("c", now.Add(time.Minute))
+	assert.Equal(t, 2, store.Len())
+	_, found = store.Reserve("b", now.Add(time.Minute))
+	assert.False(t, found, "least recently used key must be evicted")
+
+	store.Release("c")
+	_, found = store.Reserve("c", now.Add(time.Minute))
+	assert.False(t, found)
+
+	now = now.Add(2 * time.Minute)
+	_, found = store.Reserve("c", now.Add(time.Minute))
+	assert.False(t, found, "expired key must be reserved again")
+}
+
+func TestServiceCore_SetDeduplication(t *testing.T) {
+	svc := NewChargeService(diag.NewDebugLogger(10))
+	svc.SetDeduplication(time.Minute, nil)
+	svc.SetWorker(1)
+
+	first := ExecParams{IdempotencyKey: "order-1", "amount": 10}
+	first.ExpectReturn()
+	svc.Exec("charge", first)
+	assert.Equal(t, 1, first.WaitForReturn())
+
+	second := ExecParams{IdempotencyKey: "order-1", "amount": 10}
+	second.ExpectReturn()
+	svc.Exec("charge", second)
+	assert.Equal(t, 1, second.WaitForReturn(), "duplicate must receive the original result")
+
+	other := ExecParams{IdempotencyKey: "order-2", "amount": 10}
+	other.ExpectReturn()
+	svc.Exec("charge", other)
+	assert.Equal(t, 2, other.WaitForReturn())
+
+	plain := ExecParams{"amount": 10}
+	plain.ExpectReturn()
+	svc.Exec("charge", plain)
+	assert.Equal(t, 3, plain.WaitForReturn())
+	assert.Equal(t, 3, svc.count())
+	assert.Equal(t, uint64(1), svc.Duplicates())
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestServiceCore_SetDeduplication_InFlight(t *testing.T) {
+	svc := NewChargeService(diag.NewDebugLogger(10))
+	svc.SetDeduplication(time.Minute, nil)
+	svc.gate = make(chan struct{})
+	svc.SetWorker(2)
+
+	params := make([]ExecParams, 5)
+	for i := range params {
+		params[i] = ExecParams{IdempotencyKey: 7, "amount": 10}
+		params[i].ExpectReturn()
+		svc.Exec("charge", params[i])
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(svc.gate)
+	for _, p := range params {
+		assert.Equal(t, 1, p.WaitForReturn())
+	}
+	assert.Equal(t, 1, svc.count())
+	assert.Equal(t, uint64(4), svc.Duplicates())
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestServiceCore_SetDeduplication_Failure(t *testing.T) {
+	svc := NewChargeService(diag.NewDebugLogger(10))
+	svc.SetDeduplication(time.Minute, nil)
+	svc.SetWorker(1)
+
+	failed := ExecParams{IdempotencyKey: "order-1", "amount": -1}
+	failed.ExpectReturn()
+	svc.Exec("charge", failed)
+	failed.Wait()
+	assert.ErrorIs(t, failed.ReturnErr(), errChargeDeclined)
+
+	retry := ExecParams{IdempotencyKey: "order-1", "amount": 10}
+	retry.ExpectReturn()
+	svc.Exec("charge", retry)
+	assert.Equal(t, 2, retry.WaitForReturn(), "failed request must be processed again")
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestServiceCore_SetDeduplication_FireAndForget(t *testing.T) {
+	svc := NewChargeService(diag.NewDebugLogger(10))
+	svc.SetDeduplication(time.Minute, nil)
+	svc.SetInline(true)
+
+	params := ExecParams{IdempotencyKey: "order-1"}
+	svc.Exec("notify", params)
+	assert.NotContains(t, params, "return", "params of the sender must not be modified")
+	svc.Exec("notify", ExecParams{IdempotencyKey: "order-1"})
+	duplicate := ExecParams{IdempotencyKey: "order-1"}
+	duplicate.ExpectReturn()
+	svc.Exec("notify", duplicate)
+	duplicate.Wait()
+	assert.Nil(t, duplicate.ReturnResult())
+	assert.NoError(t, duplicate.ReturnErr())
+	assert.Equal(t, 1, svc.count())
+}
+
+func TestServiceCore_SetDeduplication_Window(t *testing.T) {
+	svc := NewChargeService(diag.NewDebugLogger(10))
+	svc.SetDeduplication(20*time.Millisecond, NewMemoryIdempotencyStore(10))
+	svc.SetInline(true)
+
+	svc.Exec("charge", ExecParams{IdempotencyKey: "order-1", "amount": 10})
+	svc.Exec("charge", ExecParams{IdempotencyKey: "order-1", "amount": 10})
+	assert.Equal(t, 1, svc.count())
+	time.Sleep(30 * time.Millisecond)
+	svc.Exec("charge", ExecParams{IdempotencyKey: "order-1", "amount": 10})
+	assert.Equal(t, 2, svc.count(), "key must expire after window")
+
+	svc.SetDeduplication(0, nil)
+	svc.Exec("charge", ExecParams{IdempotencyKey: "order-1", "amount": 10})
+	assert.Equal(t, 3, svc.count())
+	assert.Equal(t, uint64(0), svc.Duplicates())
+}
+
+type ChargeService struct {
+	ServiceCore
+	i *ServiceCoreInternal
+
+	gate    chan struct{}
+	mu      sync.Mutex
+	charges int
+}
+
+func NewChargeService(logger diag.Logger) *ChargeService {
+	svc := &ChargeService{}
+	svc.i = svc.InitServiceCore("Charge", logger, svc.coreProcessHook)
+	return svc
+}
+
+func (s *ChargeService) coreProcessHook(workerID uint64, msg *ServiceMessage) *HookState {
+	if msg.Command == "exit" {
+		return &HookState{Handled: false}
+	}
+	if s.gate != nil {
+		<-s.gate
+	}
+	s.mu.Lock()
+	s.charges++
+	charges := s.charges
+	s.mu.Unlock()
+	if msg.Command == "charge" {
+		if msg.GetParam("amount", 0).(int) < 0 {
+			msg.ReturnError(errChargeDeclined)
+			return &HookState{Handled: true, Error: errChargeDeclined}
+		}
+		msg.Return(charges)
+	}
+	return &HookState{Handled: true}
+}
+
+func (s *ChargeService) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.charges
+}
@@ -0,0 +1,275 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+const (
+	// Number of acknowledgements after which DurableQueue compacts its log file.
+	DurableCheckpointInterval = 1024
+	// Maximum size of an encoded record in the log file.
+	DurableMaxRecordSize = 16 << 20
+)
+
+// ErrQueueClosed is returned when using a DurableQueue after it has been closed.
+//
+// Available since v0.11.0
+var ErrQueueClosed = errors.New("queue closed")
+
+// DurableQueue is an append-only log file storing messages until they are acknowledged.
+// Messages still pending when the process stops are delivered again when the queue is reopened,
+// which gives at-least-once delivery semantics.
+//
+// Available since v0.11.0
+type DurableQueue struct {
+	mu      sync.Mutex
+	path    string
+	codec   Codec
+	file    *os.File
+	lastSeq uint64
+	pending map[uint64]*durableRecord
+	acks    int
+
+	// Number of acknowledgements after which the log file is compacted. Non-positive disables auto checkpoint.
+	CheckpointInterval int
+}
+
+// durableRecord is an entry of the log file.
+type durableRecord struct {
+	Ack     bool
+	Seq     uint64
+	Command string
+	Params  ExecParams
+}
+
+// Open the log file at path, creating it if needed, and load pending messages.
+// A partially written record at the end of the file is discarded, so is a record
+// whose length exceeds DurableMaxRecordSize and everything after it.
+//
+// Available since v0.11.0
+func OpenDurableQueue(path string, codec Codec) (*DurableQueue, error) {
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+	q := &DurableQueue{
+		path:               path,
+		codec:              codec,
+		pending:            make(map[uint64]*durableRecord),
+		CheckpointInterval: DurableCheckpointInterval,
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := q.load(file)
+	if err == nil {
+		err = file.Truncate(valid)
+	}
+	if err == nil {
+		_, err = file.Seek(valid, io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	q.file = file
+	return q, nil
+}
+
+// Persist a new message and return its sequence number.
+//
+// Available since v0.11.0
+func (q *DurableQueue) Append(command string, params ExecParams) (uint64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.file == nil {
+		return 0, ErrQueueClosed
+	}
+	record := &durableRecord{
+		Seq:     q.lastSeq + 1,
+		Command: command,
+		Params:  serializableParams(params),
+	}
+	if err := q.write(q.file, record); err != nil {
+		return 0, err
+	}
+	q.lastSeq = record.Seq
+	q.pending[record.Seq] = record
+	return record.Seq, nil
+}
+
+// Mark the message as successfully processed so it won't be delivered again.
+//
+// Available since v0.11.0
+func (q *DurableQueue) Ack(seq uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.file == nil {
+		return ErrQueueClosed
+	}
+	if _, found := q.pending[seq]; !found {
+		return nil
+	}
+	if err := q.write(q.file, &durableRecord{Ack: true, Seq: seq}); err != nil {
+		return err
+	}
+	delete(q.pending, seq)
+	q.acks++
+	if q.CheckpointInterval > 0 && q.acks >= q.CheckpointInterval {
+		return q.checkpoint()
+	}
+	return nil
+}
+
+// Compact the log file so it only contains pending messages.
+//
+// Available since v0.11.0
+func (q *DurableQueue) Checkpoint() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.file == nil {
+		return ErrQueueClosed
+	}
+	return q.checkpoint()
+}
+
+// Return pending messages in the order they were appended.
+//
+// Available since v0.11.0
+func (q *DurableQueue) Pending() []*ServiceMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	seqs := q.pendingSeqs()
+	msgs := make([]*ServiceMessage, len(seqs))
+	for i, seq := range seqs {
+		record := q.pending[seq]
+		msgs[i] = &ServiceMessage{
+			Command: record.Command,
+			Params:  record.Params.Clone(),
+			seq:     seq,
+		}
+	}
+	return msgs
+}
+
+// Return number of pending messages.
+//
+// Available since v0.11.0
+func (q *DurableQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Close the log file. Pending messages are kept for next time the queue is opened.
+//
+// Available since v0.11.0
+func (q *DurableQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.file == nil {
+		return nil
+	}
+	err := q.file.Close()
+	q.file = nil
+	return err
+}
+
+// Read all records from file and return the offset right after the last complete record.
+func (q *DurableQueue) load(file *os.File) (int64, error) {
+	reader := bufio.NewReader(file)
+	var offset int64
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return offset, nil
+		}
+		size := binary.BigEndian.Uint32(header)
+		if size > DurableMaxRecordSize {
+			return offset, nil
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return offset, nil
+		}
+		record := &durableRecord{}
+		if err := q.codec.Unmarshal(data, record); err != nil {
+			return 0, fmt.Errorf("corrupted record at offset %d: %w", offset, err)
+		}
+		if record.Ack {
+			delete(q.pending, record.Seq)
+		} else {
+			q.pending[record.Seq] = record
+		}
+		if record.Seq > q.lastSeq {
+			q.lastSeq = record.Seq
+		}
+		offset += int64(len(header) + len(data))
+	}
+}
+
+// Write a length-prefixed record then flush it to disk.
+func (q *DurableQueue) write(file *os.File, record *durableRecord) error {
+	data, err := q.codec.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if len(data) > DurableMaxRecordSize {
+		return fmt.Errorf("record of %d bytes exceeds maximum size", len(data))
+	}
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	if _, err := file.Write(buf); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// Rewrite the log file with pending messages only. Must be called with lock held.
+func (q *DurableQueue) checkpoint() error {
+	tmpPath := q.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	for _, seq := range q.pendingSeqs() {
+		if err := q.write(tmp, q.pending[seq]); err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	if err := os.Rename(tmpPath, q.path); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	q.file.Close()
+	q.file = tmp
+	q.acks = 0
+	return nil
+}
+
+// Return sequence numbers of pending messages in ascending order.
+func (q *DurableQueue) pendingSeqs() []uint64 {
+	seqs := make([]uint64, 0, len(q.pending))
+	for seq := range q.pending {
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs
+}
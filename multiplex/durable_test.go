@@ -0,0 +1,144 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestDurableQueue_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+	q, err := OpenDurableQueue(path, nil)
+	assert.NoError(t, err)
+	seq1, err := q.Append("charge", ExecParams{"amount": 100})
+	assert.NoError(t, err)
+	seq2, _ := q.Append("refund", ExecParams{"amount": 50})
+	seq3, _ := q.Append("charge", ExecParams{"amount": 10})
+	assert.Equal(t, []uint64{1, 2, 3}, []uint64{seq1, seq2, seq3})
+	assert.NoError(t, q.Ack(seq2))
+	assert.NoError(t, q.Ack(seq2), "duplicated ack must be ignored")
+	assert.Equal(t, 2, q.Len())
+	assert.NoError(t, q.Close())
+	_, err = q.Append("charge", nil)
+	assert.True(t, errors.Is(err, ErrQueueClosed))
+
+	q, err = OpenDurableQueue(path, JSONCodec{})
+	assert.NoError(t, err)
+	pending := q.Pending()
+	assert.Len(t, pending, 2)
+	assert.Equal(t, "charge", pending[0].Command)
+	assert.Equal(t, float64(100), pending[0].GetParam("amount", nil))
+	assert.Equal(t, float64(10), pending[1].GetParam("amount", nil))
+	seq4, _ := q.Append("charge", nil)
+	assert.Equal(t, uint64(4), seq4, "sequence must continue after reopen")
+	q.Close()
+}
+
+func TestDurableQueue_TornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+	q, _ := OpenDurableQueue(path, nil)
+	q.Append("charge", ExecParams{"amount": 100})
+	q.Close()
+	info, _ := os.Stat(path)
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	file.Write([]byte{0, 0, 0, 42, '{'})
+	file.Close()
+
+	q, err := OpenDurableQueue(path, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, q.Len())
+	q.Close()
+	truncated, _ := os.Stat(path)
+	assert.Equal(t, info.Size(), truncated.Size(), "partial record must be discarded")
+}
+
+func TestDurableQueue_OversizedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+	q, _ := OpenDurableQueue(path, nil)
+	q.Append("charge", ExecParams{"amount": 100})
+	q.Close()
+	info, _ := os.Stat(path)
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	file.Write([]byte{0xff, 0xff, 0xff, 0xff, '{'})
+	file.Close()
+
+	q, err := OpenDurableQueue(path, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, q.Len())
+	q.Close()
+	truncated, _ := os.Stat(path)
+	assert.Equal(t, info.Size(), truncated.Size(), "oversized record must be discarded")
+}
+
+func TestDurableQueue_Checkpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+	q, _ := OpenDurableQueue(path, nil)
+	q.CheckpointInterval = 0
+	for i := 0; i < 10; i++ {
+		seq, _ := q.Append("charge", ExecParams{"index": i})
+		if i != 5 {
+			q.Ack(seq)
+		}
+	}
+	before, _ := os.Stat(path)
+	assert.NoError(t, q.Checkpoint())
+	after, _ := os.Stat(path)
+	assert.Less(t, after.Size(), before.Size(), "log must be compacted")
+	q.Append("charge", ExecParams{"index": 10})
+	q.Close()
+
+	q, _ = OpenDurableQueue(path, nil)
+	pending := q.Pending()
+	assert.Len(t, pending, 2)
+	assert.Equal(t, float64(5), pending[0].GetParam("index", nil))
+	assert.Equal(t, float64(10), pending[1].GetParam("index", nil))
+	q.Close()
+}
+
+func TestServiceCore_SetDurableQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+	q, _ := OpenDurableQueue(path, nil)
+	logger := diag.NewDebugLogger(10)
+	svc := NewCollectorService("Billing", logger)
+	svc.Use(func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) *HookState {
+		state := next(ctx, msg)
+		if msg.Command == "fail" {
+			state.Error = errors.New("failed")
+		}
+		return state
+	})
+	svc.SetDurableQueue(q)
+	svc.SetWorker(1)
+	params := ExecParams{"amount": 100}
+	params.ExpectReturn()
+	svc.Exec("charge", params)
+	svc.Exec("fail", ExecParams{"amount": 50})
+	assert.Equal(t, "charge", svc.Receive(t).Command)
+	assert.Equal(t, "fail", svc.Receive(t).Command)
+	svc.SetWorker(0)
+	svc.WaitWorker(time.Second)
+	assert.Equal(t, 1, q.Len(), "only successful request must be acknowledged")
+	q.Close()
+
+	q, _ = OpenDurableQueue(path, nil)
+	restored := NewCollectorService("Billing", logger)
+	restored.SetDurableQueue(q)
+	restored.SetWorker(1)
+	msg := restored.Receive(t)
+	assert.Equal(t, "fail", msg.Command, "failed request must be delivered again")
+	assert.Equal(t, float64(50), msg.GetParam("amount", nil))
+	restored.SetWorker(0)
+	restored.WaitWorker(time.Second)
+	q.Close()
+}
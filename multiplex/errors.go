@@ -0,0 +1,181 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrRequestTimeout is matched by errors.Is for all RequestTimeoutError.
+//
+// Available since v0.11.0
+var ErrRequestTimeout = errors.New("request timed out")
+
+// ErrNoSuchService is matched by errors.Is for all NoSuchServiceError.
+//
+// Available since v0.11.0
+var ErrNoSuchService = errors.New("no such service")
+
+// NoSuchServiceError is reported when a message is routed to a service
+// that was never registered or has been unregistered.
+//
+// Available since v0.11.0
+type NoSuchServiceError struct {
+	ServiceID string
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *NoSuchServiceError) Error() string {
+	return fmt.Sprintf("no such service %q", e.ServiceID)
+}
+
+// Report whether target is ErrNoSuchService.
+//
+// Available since v0.11.0
+func (e *NoSuchServiceError) Is(target error) bool {
+	return target == ErrNoSuchService
+}
+
+// RequestTimeoutError is returned when the target service did not return
+// a result for a request within the timeout.
+//
+// Available since v0.11.0
+type RequestTimeoutError struct {
+	ServiceID     string
+	Command       string
+	CorrelationID string
+	Timeout       time.Duration
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *RequestTimeoutError) Error() string {
+	return fmt.Sprintf("request %s to %s (command %q) timed out after %v", e.CorrelationID, e.ServiceID, e.Command, e.Timeout)
+}
+
+// Report whether target is ErrRequestTimeout.
+//
+// Available since v0.11.0
+func (e *RequestTimeoutError) Is(target error) bool {
+	return target == ErrRequestTimeout
+}
+
+// PanicError wraps a value recovered from a panic while processing a message.
+//
+// Available since v0.11.0
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// ErrDependencyCycle is matched by errors.Is for all DependencyCycleError.
+//
+// Available since v0.11.0
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// DependencyCycleError is returned when service dependencies form a cycle.
+// Cycle starts and ends with the same service ID.
+//
+// Available since v0.11.0
+type DependencyCycleError struct {
+	Cycle []string
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *DependencyCycleError) Error() string {
+	return fmt.Sprintf("dependency cycle: %s", formatCycle(e.Cycle))
+}
+
+// Report whether target is ErrDependencyCycle.
+//
+// Available since v0.11.0
+func (e *DependencyCycleError) Is(target error) bool {
+	return target == ErrDependencyCycle
+}
+
+// MissingDependencyError is returned when a service depends on a service that is not registered.
+//
+// Available since v0.11.0
+type MissingDependencyError struct {
+	ServiceID  string
+	Dependency string
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *MissingDependencyError) Error() string {
+	return fmt.Sprintf("service %q depends on unregistered service %q", e.ServiceID, e.Dependency)
+}
+
+// Report whether target is ErrNoSuchService.
+//
+// Available since v0.11.0
+func (e *MissingDependencyError) Is(target error) bool {
+	return target == ErrNoSuchService
+}
+
+// LifecycleError is returned when a service fails to init, start or stop.
+//
+// Available since v0.11.0
+type LifecycleError struct {
+	ServiceID string
+	Phase     string
+	Err       error
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *LifecycleError) Error() string {
+	return fmt.Sprintf("service %q failed to %s: %v", e.ServiceID, e.Phase, e.Err)
+}
+
+// Return the underlying error.
+//
+// Available since v0.11.0
+func (e *LifecycleError) Unwrap() error {
+	return e.Err
+}
+
+// RestartIntensityError is reported when a supervised service crashes more often than its SupervisorSpec allows.
+//
+// Available since v0.11.0
+type RestartIntensityError struct {
+	ServiceID string
+	Restarts  int
+	Window    time.Duration
+	Err       error
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *RestartIntensityError) Error() string {
+	return fmt.Sprintf("service %q crashed %d times within %v: %v", e.ServiceID, e.Restarts, e.Window, e.Err)
+}
+
+// Return the error of the last crash.
+//
+// Available since v0.11.0
+func (e *RestartIntensityError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,181 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"runtime/debug"
+
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+// ProcessContext describes the Process routine handling a message.
+//
+// Available since v0.11.0
+type ProcessContext struct {
+	ServiceID string
+	WorkerID  uint64
+}
+
+// ProcessFunc processes a message and report the outcome.
+//
+// Available since v0.11.0
+type ProcessFunc func(ctx *ProcessContext, msg *ServiceMessage) *HookState
+
+// Interceptor wraps message processing of a ServiceCore.
+// It can inspect or modify the message before calling next, short-circuit processing
+// by returning without calling next, and observe the outcome returned by next.
+//
+// Available since v0.11.0
+type Interceptor func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) *HookState
+
+// ForwardFunc forwards a message to the specified serviceID.
+//
+// Available since v0.11.0
+type ForwardFunc func(serviceID string, msg *ServiceMessage) error
+
+// ForwardInterceptor wraps message forwarding of a ServiceRouter.
+// It can inspect or modify the message before calling next, short-circuit forwarding
+// by returning without calling next, and observe the error returned by next.
+//
+// Available since v0.11.0
+type ForwardInterceptor func(serviceID string, msg *ServiceMessage, next ForwardFunc) error
+
+// Append interceptors to the processing chain. Interceptors are called in the order they are added,
+// the first one added is the outermost. The exit request is passed to CoreProcessHook directly
+// without going through interceptors.
+//
+// Available since v0.11.0
+func (s ServiceCore) Use(interceptors ...Interceptor) {
+	s.i.chainMu.Lock()
+	defer s.i.chainMu.Unlock()
+	s.i.interceptors = append(s.i.interceptors, interceptors...)
+	s.i.chain.Store(chainProcess(s.i.baseProcess, s.i.interceptors))
+}
+
+// Append interceptors to the forwarding chain. Interceptors are called in the order they are added,
+// the first one added is the outermost.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) Use(interceptors ...ForwardInterceptor) {
+	s.chainMu.Lock()
+	defer s.chainMu.Unlock()
+	s.interceptors = append(s.interceptors, interceptors...)
+	s.chain.Store(chainForward(s.enqueue, s.interceptors))
+}
+
+// Return an Interceptor logging the start and the outcome of processing at Debug level,
+// and the error at Error level.
+//
+// Available since v0.11.0
+func LoggingInterceptor(logger diag.Logger) Interceptor {
+	return func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) *HookState {
+		logger.Debugf("%s#%d: Command %q started.", ctx.ServiceID, ctx.WorkerID, msg.Command)
+		state := next(ctx, msg)
+		if state != nil && state.Error != nil {
+			logger.Errorf(state.Error, "%s#%d: Command %q failed.", ctx.ServiceID, ctx.WorkerID, msg.Command)
+		} else {
+			logger.Debugf("%s#%d: Command %q completed.", ctx.ServiceID, ctx.WorkerID, msg.Command)
+		}
+		return state
+	}
+}
+
+// Return an Interceptor logging time spent to process a message at Debug level.
+//
+// Available since v0.11.0
+func TimingInterceptor(logger diag.Logger) Interceptor {
+	return func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) *HookState {
+		timer := diag.NewTimer()
+		state := next(ctx, msg)
+		logger.Debugf("%s#%d: Command %q took %v.", ctx.ServiceID, ctx.WorkerID, msg.Command, timer.Duration())
+		return state
+	}
+}
+
+// Return an Interceptor recovering panics raised while processing a message.
+// The panic is logged, reported as PanicError in HookState and returned to the sender if it expects returns.
+//
+// Available since v0.11.0
+func RecoveryInterceptor(logger diag.Logger) Interceptor {
+	return func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) (state *HookState) {
+		defer func() {
+			if r := recover(); r != nil {
+				err := &PanicError{
+					Value: r,
+					Stack: debug.Stack(),
+				}
+				logger.Errorf(err, "%s#%d: Command %q panicked.", ctx.ServiceID, ctx.WorkerID, msg.Command)
+				msg.ReturnError(err)
+				state = &HookState{Handled: true, Error: err}
+			}
+		}()
+		return next(ctx, msg)
+	}
+}
+
+// Return a ForwardInterceptor logging forwarded messages at Debug level, and the error at Error level.
+//
+// Available since v0.11.0
+func LoggingForwardInterceptor(logger diag.Logger) ForwardInterceptor {
+	return func(serviceID string, msg *ServiceMessage, next ForwardFunc) error {
+		err := next(serviceID, msg)
+		if err != nil {
+			logger.Errorf(err, "Router: Command %q to %s failed.", msg.Command, serviceID)
+		} else {
+			logger.Debugf("Router: Command %q forwarded to %s.", msg.Command, serviceID)
+		}
+		return err
+	}
+}
+
+// Return the processing chain including interceptors.
+func (i *ServiceCoreInternal) processFunc() ProcessFunc {
+	if chain, ok := i.chain.Load().(ProcessFunc); ok {
+		return chain
+	}
+	return i.baseProcess
+}
+
+// Process the message with CoreProcessHook.
+func (i *ServiceCoreInternal) baseProcess(ctx *ProcessContext, msg *ServiceMessage) *HookState {
+	if i.CoreProcessHook == nil {
+		return &HookState{Handled: false}
+	}
+	return i.CoreProcessHook(ctx.WorkerID, msg)
+}
+
+// Return the forwarding chain including interceptors.
+func (s *ServiceRouter) forwardFunc() ForwardFunc {
+	if chain, ok := s.chain.Load().(ForwardFunc); ok {
+		return chain
+	}
+	return s.enqueue
+}
+
+// Wrap base with interceptors, the first interceptor is the outermost.
+func chainProcess(base ProcessFunc, interceptors []Interceptor) ProcessFunc {
+	chain := base
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], chain
+		chain = func(ctx *ProcessContext, msg *ServiceMessage) *HookState {
+			return interceptor(ctx, msg, next)
+		}
+	}
+	return chain
+}
+
+// Wrap base with interceptors, the first interceptor is the outermost.
+func chainForward(base ForwardFunc, interceptors []ForwardInterceptor) ForwardFunc {
+	chain := base
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], chain
+		chain = func(serviceID string, msg *ServiceMessage) error {
+			return interceptor(serviceID, msg, next)
+		}
+	}
+	return chain
+}
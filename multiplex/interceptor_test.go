@@ -0,0 +1,131 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestServiceCore_Use(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewCollectorService("Collector", logger)
+	order := []string{}
+	svc.Use(func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) *HookState {
+		order = append(order, "outer")
+		msg.SetParam("intercepted", true)
+		return next(ctx, msg)
+	}, func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) *HookState {
+		order = append(order, "inner")
+		if msg.Command == "denied" {
+			return &HookState{Handled: true, Error: errors.New("denied")}
+		}
+		return next(ctx, msg)
+	})
+	svc.SetWorker(1)
+	svc.Exec("denied", nil)
+	svc.Exec("allowed", nil)
+	msg := svc.Receive(t)
+	assert.Equal(t, "allowed", msg.Command, "denied message must be short-circuited")
+	assert.Equal(t, true, msg.GetParam("intercepted", false), "message must be modified")
+	assert.Equal(t, []string{"outer", "inner", "outer", "inner"}, order)
+}
+
+func TestServiceCore_Use_Exit(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewCollectorService("Collector", logger)
+	svc.Use(func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) *HookState {
+		return &HookState{Handled: true, Error: errors.New("rejected")}
+	})
+	svc.SetWorker(2)
+	assert.True(t, svc.WaitWorker(time.Second))
+	svc.SetWorker(1)
+	assert.True(t, waitCondition(func() bool { return svc.i.WorkerCounter.Value() == 1 }), "exit must not be rejected by interceptors")
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewIdleService(logger)
+	svc.Use(LoggingInterceptor(logger), func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) *HookState {
+		if msg.Command == "fail" {
+			return &HookState{Handled: true, Error: errors.New("failed")}
+		}
+		return next(ctx, msg)
+	})
+	svc.SetWorker(1)
+	svc.Exec("work", nil)
+	svc.Exec("fail", nil)
+	svc.SetWorker(0)
+	svc.WaitWorker(time.Second)
+	messages := logger.AllMessages()
+	assert.Contains(t, messages, `DEBUG Idle#1: Command "work" started.`)
+	assert.Contains(t, messages, `DEBUG Idle#1: Command "work" completed.`)
+	assert.Contains(t, messages, `ERROR failed Idle#1: Command "fail" failed.`)
+}
+
+func TestTimingInterceptor(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewIdleService(logger)
+	svc.Use(TimingInterceptor(logger))
+	svc.SetWorker(1)
+	svc.Exec("work", nil)
+	svc.SetWorker(0)
+	svc.WaitWorker(time.Second)
+	messages := logger.AllMessages()
+	assert.Contains(t, messages[len(messages)-2], `DEBUG Idle#1: Command "work" took `)
+	for _, message := range messages {
+		assert.NotContains(t, message, `Command "exit"`, "exit must not pass through interceptors")
+	}
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	svc := NewEchoService(logger)
+	svc.Use(RecoveryInterceptor(logger))
+	svc.SetWorker(1)
+	params := ExecParams{}
+	params.ExpectReturn()
+	svc.Exec("", params)
+	assert.Nil(t, params.WaitForReturn())
+	var panicErr *PanicError
+	assert.True(t, errors.As(params.ReturnErr(), &panicErr), "panic must be returned to sender")
+	svc.Exec("", ExecParams{"message": "Hello, World!"})
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, "INFO Echo#1: Message received: Hello, World!", logger.LastMessage(), "worker must survive the panic")
+}
+
+func TestServiceRouter_Use(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	collector := NewCollectorService("Collector", logger)
+	collector.SetRouter(controller)
+	collector.SetWorker(1)
+	controller.Register(collector)
+	controller.Run(false)
+	errDenied := errors.New("denied")
+	controller.Router().Use(LoggingForwardInterceptor(logger), func(serviceID string, msg *ServiceMessage, next ForwardFunc) error {
+		if msg.Command == "denied" {
+			return errDenied
+		}
+		msg.SetParam("intercepted", true)
+		return next(serviceID, msg)
+	})
+	err := controller.Router().ForwardE("Collector", "denied", nil)
+	assert.Equal(t, errDenied, err)
+	assert.Equal(t, `ERROR denied Router: Command "denied" to Collector failed.`, logger.LastMessage())
+	err = controller.Router().ForwardE("Collector", "allowed", nil)
+	assert.NoError(t, err)
+	msg := collector.Receive(t)
+	assert.Equal(t, "allowed", msg.Command)
+	assert.Equal(t, true, msg.GetParam("intercepted", false))
+}
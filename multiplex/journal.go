@@ -0,0 +1,354 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Number of entries a Journal keeps in memory by default.
+	DefaultJournalCapacity = 1024
+)
+
+// JournalOutcome describes what happened to a journaled message.
+//
+// Available since v0.11.0
+type JournalOutcome string
+
+const (
+	// The message was accepted by the Router, no result is expected.
+	JournalForwarded JournalOutcome = "forwarded"
+
+	// The Router failed to forward the message.
+	JournalDropped JournalOutcome = "dropped"
+
+	// The message was accepted by the Router and its result is not returned yet.
+	JournalPending JournalOutcome = "pending"
+
+	// The target service returned a result.
+	JournalReturned JournalOutcome = "returned"
+
+	// The target service returned an error.
+	JournalFailed JournalOutcome = "failed"
+)
+
+// JournalEntry is a message recorded by a Journal.
+//
+// Available since v0.11.0
+type JournalEntry struct {
+	Seq     uint64          `json:"seq"`
+	Time    time.Time       `json:"time"`
+	Source  string          `json:"source,omitempty"`
+	Target  string          `json:"target,omitempty"`
+	Command string          `json:"command,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Outcome JournalOutcome  `json:"outcome"`
+	Error   string          `json:"error,omitempty"`
+
+	// Error encountered while serializing params, if any.
+	ParamsError string `json:"params_error,omitempty"`
+	// Time elapsed until the result was returned.
+	Duration time.Duration `json:"duration,omitempty"`
+
+	recorded bool
+}
+
+// Journal records messages routed by a ServiceController in a bounded in-memory buffer,
+// and optionally appends them to a file in JSON lines format.
+// Params are serialized with JSONCodec, entries only meaningful within the process are omitted.
+//
+// Available since v0.11.0
+type Journal struct {
+	mu       sync.Mutex
+	capacity int
+	entries  []*JournalEntry
+	next     int
+	lastSeq  uint64
+	file     *os.File
+	writer   *bufio.Writer
+	now      func() time.Time
+}
+
+// Return new in-memory Journal keeping the last capacity entries.
+// Non-positive capacity means DefaultJournalCapacity.
+//
+// Available since v0.11.0
+func NewJournal(capacity int) *Journal {
+	if capacity <= 0 {
+		capacity = DefaultJournalCapacity
+	}
+	return &Journal{
+		capacity: capacity,
+		entries:  make([]*JournalEntry, 0, capacity),
+		now:      time.Now,
+	}
+}
+
+// Return new Journal keeping the last capacity entries in memory and appending all entries
+// to the file at path, which is created if needed.
+//
+// Available since v0.11.0
+func OpenJournal(path string, capacity int) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	journal := NewJournal(capacity)
+	journal.file = file
+	journal.writer = bufio.NewWriter(file)
+	return journal, nil
+}
+
+// Load all entries from a Journal file, merging outcomes of requests into their entries.
+//
+// Available since v0.11.0
+func ReadJournal(path string) ([]JournalEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	entries := []JournalEntry{}
+	index := make(map[uint64]int)
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		var entry JournalEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return entries, err
+		}
+		if i, found := index[entry.Seq]; found && entry.Target == "" {
+			entries[i].Outcome = entry.Outcome
+			entries[i].Error = entry.Error
+			entries[i].Duration = entry.Duration
+			continue
+		}
+		index[entry.Seq] = len(entries)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Return entries kept in memory, oldest first.
+//
+// Available since v0.11.0
+func (j *Journal) Entries() []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entries := make([]JournalEntry, 0, len(j.entries))
+	if len(j.entries) == j.capacity {
+		for _, entry := range j.entries[j.next:] {
+			entries = append(entries, *entry)
+		}
+		for _, entry := range j.entries[:j.next] {
+			entries = append(entries, *entry)
+		}
+		return entries
+	}
+	for _, entry := range j.entries {
+		entries = append(entries, *entry)
+	}
+	return entries
+}
+
+// Flush and close the Journal file, if any.
+//
+// Available since v0.11.0
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.writer.Flush()
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	j.file = nil
+	return err
+}
+
+// Record messages routed by the controller, including published and scheduled messages, into the Journal.
+// Nil journal stops recording.
+//
+// Available since v0.11.0
+func (s *ServiceController) SetJournal(journal *Journal) {
+	s.journal.Store(journal)
+}
+
+// Return the Journal recording messages routed by the controller, if any.
+//
+// Available since v0.11.0
+func (s *ServiceController) Journal() *Journal {
+	journal, _ := s.journal.Load().(*Journal)
+	return journal
+}
+
+// Create the entry of a message about to be forwarded, and observe its result if it expects returns.
+func (j *Journal) begin(target string, msg *ServiceMessage, report func(err error)) *JournalEntry {
+	j.mu.Lock()
+	j.lastSeq++
+	entry := &JournalEntry{
+		Seq:     j.lastSeq,
+		Time:    j.now(),
+		Source:  msg.Source(),
+		Target:  target,
+		Command: msg.Command,
+	}
+	j.mu.Unlock()
+	if msg.Params != nil {
+		params, err := JSONCodec{}.Marshal(serializableParams(msg.Params))
+		if err != nil {
+			entry.ParamsError = err.Error()
+		} else {
+			entry.Params = params
+		}
+		if ret, ok := msg.Params["return"].(*ReturnParams); ok {
+			entry.Outcome = JournalPending
+			callback := ret.callback
+			ret.callback = func(result interface{}, err error) {
+				if journalErr := j.complete(entry, err); journalErr != nil {
+					report(journalErr)
+				}
+				if callback != nil {
+					callback(result, err)
+				}
+			}
+		}
+	}
+	return entry
+}
+
+// Record the entry once the Router has forwarded the message.
+func (j *Journal) forwarded(entry *JournalEntry, err error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err != nil {
+		entry.Outcome = JournalDropped
+		entry.Error = err.Error()
+	} else if entry.Outcome == "" {
+		entry.Outcome = JournalForwarded
+	}
+	entry.recorded = true
+	if len(j.entries) < j.capacity {
+		j.entries = append(j.entries, entry)
+	} else {
+		j.entries[j.next] = entry
+		j.next = (j.next + 1) % j.capacity
+	}
+	return j.write(entry)
+}
+
+// Update the entry with the result returned by the target service.
+func (j *Journal) complete(entry *JournalEntry, err error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if entry.Outcome != JournalPending {
+		return nil
+	}
+	entry.Outcome = JournalReturned
+	if err != nil {
+		entry.Outcome = JournalFailed
+		entry.Error = err.Error()
+	}
+	entry.Duration = j.now().Sub(entry.Time)
+	if !entry.recorded {
+		return nil
+	}
+	return j.write(&JournalEntry{
+		Seq:      entry.Seq,
+		Time:     entry.Time,
+		Outcome:  entry.Outcome,
+		Error:    entry.Error,
+		Duration: entry.Duration,
+	})
+}
+
+// Append the entry to the Journal file, if any. Must be called with lock held.
+func (j *Journal) write(entry *JournalEntry) error {
+	if j.file == nil {
+		return nil
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := j.writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return j.writer.Flush()
+}
+
+// ReplayConfig defines which journaled messages are replayed and how.
+//
+// Available since v0.11.0
+type ReplayConfig struct {
+	// Return whether the entry should be replayed. By default, only messages sent from outside of services
+	// are replayed, as messages sent by services are expected to be sent again while replaying.
+	Filter func(entry *JournalEntry) bool
+	// Replay messages the Router failed to forward originally, which are skipped by default
+	// as they never reached their target.
+	IncludeDropped bool
+	// Factor applied to original delays between replayed messages. Zero replays without delay.
+	TimeScale float64
+}
+
+// Forward journaled messages to the controller's Router again, in their original order.
+// Numbers in params are restored as float64.
+// Return number of messages forwarded successfully and the first error encountered.
+//
+// Available since v0.11.0
+func ReplayJournal(controller *ServiceController, entries []JournalEntry, config ReplayConfig) (int, error) {
+	if config.Filter == nil {
+		config.Filter = func(entry *JournalEntry) bool {
+			return entry.Source == ""
+		}
+	}
+	sorted := make([]JournalEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Seq < sorted[j].Seq
+	})
+	var firstErr error
+	var last time.Time
+	count := 0
+	for i := range sorted {
+		entry := &sorted[i]
+		if entry.Outcome == JournalDropped && !config.IncludeDropped {
+			continue
+		}
+		if !config.Filter(entry) {
+			continue
+		}
+		if config.TimeScale > 0 && !last.IsZero() {
+			time.Sleep(time.Duration(float64(entry.Time.Sub(last)) * config.TimeScale))
+		}
+		last = entry.Time
+		var params ExecParams
+		if len(entry.Params) > 0 {
+			if err := (JSONCodec{}).Unmarshal(entry.Params, &params); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+		}
+		if err := controller.Router().ForwardE(entry.Target, entry.Command, params); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		count++
+	}
+	return count, firstErr
+}
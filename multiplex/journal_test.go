@@ -0,0 +1,144 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceController_SetJournal(t *testing.T) {
+	controller, upper := newTransportTestController()
+	defer controller.SetWorker(0)
+	journal := NewJournal(10)
+	controller.SetJournal(journal)
+	router := controller.Router()
+
+	router.Forward("Upper", "relay", ExecParams{"text": "hi", "target": "Hidden"})
+	_, err := router.Request("Upper", "upper", ExecParams{"text": "hi"}, time.Second)
+	assert.NoError(t, err)
+	_, err = router.Request("Upper", "fail", ExecParams{"text": "hi"}, time.Second)
+	assert.Error(t, err)
+	assert.Error(t, router.ForwardE("Unknown", "upper", nil))
+	assert.True(t, waitCondition(func() bool { return len(journal.Entries()) == 5 }))
+	assert.Empty(t, upper.all())
+
+	entries := journal.Entries()
+	type summary struct {
+		Source, Target, Command string
+		Outcome                 JournalOutcome
+	}
+	summaries := []summary{}
+	for _, entry := range entries {
+		summaries = append(summaries, summary{entry.Source, entry.Target, entry.Command, entry.Outcome})
+	}
+	assert.ElementsMatch(t, []summary{
+		{"", "Upper", "relay", JournalForwarded},
+		{"Upper", "Hidden", "record", JournalForwarded},
+		{"", "Upper", "upper", JournalReturned},
+		{"", "Upper", "fail", JournalFailed},
+		{"", "Unknown", "upper", JournalDropped},
+	}, summaries)
+	for _, entry := range entries {
+		if entry.Command == "upper" && entry.Outcome == JournalReturned {
+			assert.JSONEq(t, `{"text":"hi","correlation_id":"1"}`, string(entry.Params))
+		}
+		if entry.Command == "fail" {
+			assert.Equal(t, "cannot process hi", entry.Error)
+		}
+	}
+
+	controller.SetJournal(nil)
+	router.Forward("Upper", "record", ExecParams{"text": "untracked"})
+	assert.Len(t, journal.Entries(), 5)
+}
+
+func TestServiceController_SetJournal_PublishSchedule(t *testing.T) {
+	controller, upper := newTransportTestController()
+	defer controller.SetWorker(0)
+	journal := NewJournal(10)
+	controller.SetJournal(journal)
+	assert.NoError(t, controller.Subscribe("Upper", "orders.*"))
+
+	count, err := controller.Router().Publish("orders.created", "record", ExecParams{"text": "published"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	_, err = upper.ExecAfter(time.Millisecond, "record", ExecParams{"text": "scheduled"})
+	assert.NoError(t, err)
+	assert.True(t, waitCondition(func() bool { return len(upper.all()) == 2 }))
+
+	entries := journal.Entries()
+	assert.Len(t, entries, 2)
+	sources := []string{}
+	for _, entry := range entries {
+		assert.Equal(t, "Upper", entry.Target)
+		assert.Equal(t, JournalForwarded, entry.Outcome)
+		sources = append(sources, entry.Source)
+	}
+	assert.ElementsMatch(t, []string{"", "Upper"}, sources)
+}
+
+func TestJournal_Capacity(t *testing.T) {
+	journal := NewJournal(3)
+	for i := 0; i < 5; i++ {
+		entry := journal.begin("Target", &ServiceMessage{Command: "command"}, nil)
+		assert.NoError(t, journal.forwarded(entry, nil))
+	}
+	seqs := []uint64{}
+	for _, entry := range journal.Entries() {
+		seqs = append(seqs, entry.Seq)
+	}
+	assert.Equal(t, []uint64{3, 4, 5}, seqs)
+}
+
+func TestReplayJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := OpenJournal(path, 0)
+	assert.NoError(t, err)
+	controller, _ := newTransportTestController()
+	defer controller.SetWorker(0)
+	controller.SetJournal(journal)
+	controller.Router().Forward("Upper", "relay", ExecParams{"text": "first", "target": "Upper"})
+	controller.Router().Forward("Upper", "record", ExecParams{"text": "second"})
+	_, err = controller.Router().Request("Upper", "upper", ExecParams{"text": "third"}, time.Second)
+	assert.NoError(t, err)
+	assert.Error(t, controller.Router().ForwardE("Unknown", "record", ExecParams{"text": "dropped"}))
+	assert.True(t, waitCondition(func() bool { return len(journal.Entries()) == 5 }))
+	assert.NoError(t, journal.Close())
+
+	entries, err := ReadJournal(path)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 5)
+	for _, entry := range entries {
+		if entry.Command == "upper" {
+			assert.Equal(t, JournalReturned, entry.Outcome)
+		}
+	}
+
+	replayed, upper := newTransportTestController()
+	defer replayed.SetWorker(0)
+	count, err := ReplayJournal(replayed, entries, ReplayConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count, "messages sent by services must not be replayed")
+	assert.True(t, waitCondition(func() bool { return len(upper.all()) == 2 }))
+	assert.ElementsMatch(t, []string{"first", "second"}, upper.all())
+
+	count, err = ReplayJournal(replayed, entries, ReplayConfig{
+		Filter: func(entry *JournalEntry) bool {
+			return entry.Target == "Unknown"
+		},
+		IncludeDropped: true,
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 0, count)
+
+	_, err = ReadJournal(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.Error(t, err)
+}
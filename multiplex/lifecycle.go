@@ -0,0 +1,199 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"sort"
+	"strings"
+)
+
+// LifecycleService is implemented by services that need to be notified by ServiceController
+// when it starts and stops. Init is called for all services before any Start.
+//
+// Available since v0.11.0
+type LifecycleService interface {
+	Service
+
+	// Prepare resources of the service.
+	Init() error
+
+	// Start the service, its dependencies have already been started.
+	Start() error
+
+	// Stop the service, services depending on it have already been stopped.
+	// Stop is also called if the service was initialized but Init of another service failed,
+	// or if the service was started but Start of another service failed.
+	// A service whose Init or Start failed is never stopped, it must release its resources itself.
+	Stop() error
+}
+
+// Declare that serviceID depends on other services. Dependencies are started before
+// and stopped after the service.
+//
+// Available since v0.11.0
+func (s *ServiceController) DependsOn(serviceID string, dependencies ...string) {
+	s.servicesMu.Lock()
+	defer s.servicesMu.Unlock()
+	for _, dependency := range dependencies {
+		found := false
+		for _, existing := range s.dependencies[serviceID] {
+			if existing == dependency {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.dependencies[serviceID] = append(s.dependencies[serviceID], dependency)
+		}
+	}
+}
+
+// Return registered services in the order they should be started, dependencies first.
+// Services without dependency relation are ordered by their IDs.
+// DependencyCycleError or MissingDependencyError is returned if the order can't be resolved.
+//
+// Available since v0.11.0
+func (s *ServiceController) StartupOrder() ([]string, error) {
+	s.servicesMu.RLock()
+	defer s.servicesMu.RUnlock()
+	serviceIDs := make([]string, 0, len(s.services))
+	for serviceID := range s.services {
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	sort.Strings(serviceIDs)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := make(map[string]int)
+	order := make([]string, 0, len(serviceIDs))
+	path := []string{}
+	var visit func(serviceID string) error
+	visit = func(serviceID string) error {
+		switch states[serviceID] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, id := range path {
+				if id == serviceID {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), serviceID)
+			return &DependencyCycleError{Cycle: cycle}
+		}
+		states[serviceID] = visiting
+		path = append(path, serviceID)
+		dependencies := append([]string{}, s.dependencies[serviceID]...)
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			if _, found := s.services[dependency]; !found {
+				return &MissingDependencyError{ServiceID: serviceID, Dependency: dependency}
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[serviceID] = visited
+		order = append(order, serviceID)
+		return nil
+	}
+	for _, serviceID := range serviceIDs {
+		if err := visit(serviceID); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Init then Start all registered LifecycleService in dependency order.
+// If Init of a service fails, the services already initialized are stopped in reverse order.
+// If Start of a service fails, only the services already started are stopped in reverse order,
+// the failed service and the services after it are not stopped.
+// LifecycleError is returned in both cases. Calling Start again before Stop does nothing.
+//
+// Available since v0.11.0
+func (s *ServiceController) Start() error {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	if s.lifecycleStarted {
+		return nil
+	}
+	order, err := s.StartupOrder()
+	if err != nil {
+		return err
+	}
+	services := s.lifecycleServices(order)
+	for i, service := range services {
+		if err := service.Init(); err != nil {
+			s.stopServices(services[:i])
+			return &LifecycleError{ServiceID: service.ServiceID(), Phase: "init", Err: err}
+		}
+	}
+	for i, service := range services {
+		if err := service.Start(); err != nil {
+			s.stopServices(services[:i])
+			return &LifecycleError{ServiceID: service.ServiceID(), Phase: "start", Err: err}
+		}
+		s.i.Logger.Infof("Service %s started.", service.ServiceID())
+	}
+	s.started = services
+	s.lifecycleStarted = true
+	return nil
+}
+
+// Stop all started LifecycleService in reverse order of their startup.
+// All services are stopped even if some fail, the first error is returned as LifecycleError.
+//
+// Available since v0.11.0
+func (s *ServiceController) Stop() error {
+	s.lifecycleMu.Lock()
+	defer s.lifecycleMu.Unlock()
+	started := s.started
+	s.started = nil
+	s.lifecycleStarted = false
+	return s.stopServices(started)
+}
+
+// Stop the services in reverse order, the first error is returned as LifecycleError.
+func (s *ServiceController) stopServices(services []LifecycleService) error {
+	var firstErr error
+	for i := len(services) - 1; i >= 0; i-- {
+		service := services[i]
+		if err := service.Stop(); err != nil {
+			s.i.Logger.Errorf(err, "Service %s failed to stop.", service.ServiceID())
+			if firstErr == nil {
+				firstErr = &LifecycleError{ServiceID: service.ServiceID(), Phase: "stop", Err: err}
+			}
+			continue
+		}
+		s.i.Logger.Infof("Service %s stopped.", service.ServiceID())
+	}
+	return firstErr
+}
+
+// Return registered services implementing LifecycleService in the specified order.
+func (s *ServiceController) lifecycleServices(order []string) []LifecycleService {
+	services := []LifecycleService{}
+	for _, serviceID := range order {
+		service, _ := s.Service(serviceID)
+		if lifecycle, ok := service.(LifecycleService); ok {
+			services = append(services, lifecycle)
+		}
+	}
+	return services
+}
+
+// Format the dependency cycle for error message.
+func formatCycle(cycle []string) string {
+	return strings.Join(cycle, " -> ")
+}
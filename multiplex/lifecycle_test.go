@@ -0,0 +1,240 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestServiceController_StartupOrder(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	for _, serviceID := range []string{"Api", "Cache", "Database", "Mailer"} {
+		svc := NewIdleService(logger)
+		svc.i.ServiceID = serviceID
+		svc.SetRouter(controller)
+		controller.Register(svc)
+	}
+	controller.DependsOn("Api", "Cache", "Database")
+	controller.DependsOn("Cache", "Database")
+	controller.DependsOn("Cache", "Database")
+	order, err := controller.StartupOrder()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Database", "Cache", "Api", "Mailer"}, order)
+
+	controller.DependsOn("Database", "Api")
+	_, err = controller.StartupOrder()
+	assert.True(t, errors.Is(err, ErrDependencyCycle))
+	var cycleErr *DependencyCycleError
+	assert.True(t, errors.As(err, &cycleErr))
+	assert.Equal(t, []string{"Api", "Cache", "Database", "Api"}, cycleErr.Cycle)
+	assert.EqualError(t, err, "dependency cycle: Api -> Cache -> Database -> Api")
+}
+
+func TestServiceController_StartupOrder_Missing(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	svc := NewIdleService(logger)
+	svc.SetRouter(controller)
+	controller.Register(svc)
+	controller.DependsOn("Idle", "Database")
+	_, err := controller.StartupOrder()
+	var missingErr *MissingDependencyError
+	assert.True(t, errors.As(err, &missingErr))
+	assert.Equal(t, "Database", missingErr.Dependency)
+	assert.True(t, errors.Is(err, ErrNoSuchService))
+}
+
+func TestServiceController_StartStop(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	events := &lifecycleEvents{}
+	NewLifecycleTestService("Api", controller, events)
+	NewLifecycleTestService("Database", controller, events)
+	idle := NewIdleService(logger)
+	idle.SetRouter(controller)
+	controller.Register(idle)
+	controller.DependsOn("Api", "Database", "Idle")
+	assert.NoError(t, controller.Start())
+	assert.Equal(t, []string{"Database.init", "Api.init", "Database.start", "Api.start"}, events.all())
+	events.reset()
+	assert.NoError(t, controller.Stop())
+	assert.Equal(t, []string{"Api.stop", "Database.stop"}, events.all())
+	events.reset()
+	assert.NoError(t, controller.Stop(), "services must be stopped only once")
+	assert.Empty(t, events.all())
+}
+
+func TestServiceController_Start_Failure(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	events := &lifecycleEvents{}
+	NewLifecycleTestService("Database", controller, events)
+	NewLifecycleTestService("Cache", controller, events)
+	api := NewLifecycleTestService("Api", controller, events)
+	api.startErr = errors.New("port in use")
+	controller.DependsOn("Api", "Cache")
+	controller.DependsOn("Cache", "Database")
+	err := controller.Start()
+	var lifecycleErr *LifecycleError
+	assert.True(t, errors.As(err, &lifecycleErr))
+	assert.Equal(t, "Api", lifecycleErr.ServiceID)
+	assert.Equal(t, "start", lifecycleErr.Phase)
+	assert.EqualError(t, errors.Unwrap(err), "port in use")
+	assert.Equal(t, []string{
+		"Database.init", "Cache.init", "Api.init",
+		"Database.start", "Cache.start", "Api.start",
+		"Cache.stop", "Database.stop",
+	}, events.all())
+}
+
+func TestServiceController_Start_FailureStopsStartedOnly(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	events := &lifecycleEvents{}
+	NewLifecycleTestService("Database", controller, events)
+	cache := NewLifecycleTestService("Cache", controller, events)
+	cache.startErr = errors.New("cache unavailable")
+	NewLifecycleTestService("Api", controller, events)
+	controller.DependsOn("Api", "Cache")
+	controller.DependsOn("Cache", "Database")
+	err := controller.Start()
+	var lifecycleErr *LifecycleError
+	assert.True(t, errors.As(err, &lifecycleErr))
+	assert.Equal(t, "Cache", lifecycleErr.ServiceID)
+	assert.Equal(t, []string{
+		"Database.init", "Cache.init", "Api.init",
+		"Database.start", "Cache.start",
+		"Database.stop",
+	}, events.all(), "failed service and services after it must not be stopped")
+}
+
+func TestServiceController_Run_Lifecycle(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	events := &lifecycleEvents{}
+	NewLifecycleTestService("Database", controller, events)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		controller.Exec("exit", ExecParams{})
+	}()
+	controller.Run(true)
+	assert.Equal(t, []string{"Database.init", "Database.start", "Database.stop"}, events.all())
+
+	controller = NewServiceController(logger)
+	NewLifecycleTestService("Api", controller, events)
+	controller.DependsOn("Api", "Api")
+	controller.Run(true)
+	assert.Equal(t, "ERROR dependency cycle: Api -> Api Controller failed to start services.", logger.LastMessage())
+}
+
+func TestServiceController_Start_InitFailure(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	events := &lifecycleEvents{}
+	NewLifecycleTestService("Database", controller, events)
+	NewLifecycleTestService("Cache", controller, events)
+	api := NewLifecycleTestService("Api", controller, events)
+	api.initErr = errors.New("bad config")
+	controller.DependsOn("Api", "Cache")
+	controller.DependsOn("Cache", "Database")
+	err := controller.Start()
+	var lifecycleErr *LifecycleError
+	assert.True(t, errors.As(err, &lifecycleErr))
+	assert.Equal(t, "init", lifecycleErr.Phase)
+	assert.Equal(t, []string{
+		"Database.init", "Cache.init", "Api.init",
+		"Cache.stop", "Database.stop",
+	}, events.all())
+}
+
+func TestServiceController_Start_Idempotent(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	events := &lifecycleEvents{}
+	NewLifecycleTestService("Database", controller, events)
+	assert.NoError(t, controller.Start())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		controller.Exec("exit", ExecParams{})
+	}()
+	assert.NoError(t, controller.RunE(true))
+	assert.Equal(t, []string{"Database.init", "Database.start", "Database.stop"}, events.all())
+}
+
+func TestServiceController_RunE(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	events := &lifecycleEvents{}
+	api := NewLifecycleTestService("Api", controller, events)
+	api.startErr = errors.New("port in use")
+	err := controller.RunE(true)
+	var lifecycleErr *LifecycleError
+	assert.True(t, errors.As(err, &lifecycleErr))
+	assert.Equal(t, "Api", lifecycleErr.ServiceID)
+	assert.Equal(t, uint64(0), controller.WorkerCount())
+}
+
+type lifecycleEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (e *lifecycleEvents) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, event)
+}
+
+func (e *lifecycleEvents) all() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string{}, e.events...)
+}
+
+func (e *lifecycleEvents) reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = nil
+}
+
+type LifecycleTestService struct {
+	ServiceCore
+	i        *ServiceCoreInternal
+	events   *lifecycleEvents
+	initErr  error
+	startErr error
+}
+
+func NewLifecycleTestService(serviceID string, controller *ServiceController, events *lifecycleEvents) *LifecycleTestService {
+	svc := &LifecycleTestService{events: events}
+	svc.i = svc.InitServiceCore(serviceID, diag.NewDebugLogger(10), nil)
+	svc.SetRouter(controller)
+	controller.Register(svc)
+	return svc
+}
+
+func (s *LifecycleTestService) Init() error {
+	s.events.add(s.ServiceID() + ".init")
+	return s.initErr
+}
+
+func (s *LifecycleTestService) Start() error {
+	s.events.add(s.ServiceID() + ".start")
+	return s.startErr
+}
+
+func (s *LifecycleTestService) Stop() error {
+	s.events.add(s.ServiceID() + ".stop")
+	return nil
+}
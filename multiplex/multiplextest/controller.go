@@ -0,0 +1,304 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplextest
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tforce-io/tf-golib/diag"
+	"github.com/tforce-io/tf-golib/multiplex"
+)
+
+// Service is a service embedding multiplex.ServiceCore.
+//
+// Available since v0.11.0
+type Service interface {
+	multiplex.Service
+	SetRouter(controller *multiplex.ServiceController)
+	SetInline(inline bool)
+	Unprocessed() int
+}
+
+// Idler reports number of requests a service has not processed yet.
+//
+// Available since v0.11.0
+type Idler interface {
+	ServiceID() string
+	Unprocessed() int
+}
+
+// Dispatch is a message forwarded via the Router of a Controller.
+//
+// Available since v0.11.0
+type Dispatch struct {
+	ServiceID string
+	Command   string
+	Params    multiplex.ExecParams
+}
+
+// Controller is a ServiceController recording all messages forwarded via its Router.
+// Messages to services added to the Controller are delivered directly to them,
+// messages to other services are only recorded, so collaborators don't need to be registered.
+//
+// Available since v0.11.0
+type Controller struct {
+	*multiplex.ServiceController
+	Logger *diag.DebugLogger
+
+	tb       testing.TB
+	inline   bool
+	mu       sync.Mutex
+	services []Service
+	records  []*Dispatch
+}
+
+// Return new Controller. Services added to it process requests in their Process routines.
+//
+// Available since v0.11.0
+func NewController(tb testing.TB) *Controller {
+	return newController(tb, false)
+}
+
+// Return new Controller. Services added to it process requests inline,
+// so Exec, Dispatch and Request return after the request and all messages it forwards are processed.
+//
+// Available since v0.11.0
+func NewSyncController(tb testing.TB) *Controller {
+	return newController(tb, true)
+}
+
+// Return new Controller with specified processing mode.
+func newController(tb testing.TB, inline bool) *Controller {
+	logger := diag.NewDebugLogger(100)
+	c := &Controller{
+		ServiceController: multiplex.NewServiceController(logger),
+		Logger:            logger,
+		tb:                tb,
+		inline:            inline,
+	}
+	c.Router().Use(c.intercept)
+	return c
+}
+
+// Register services to the Controller.
+// In synchronous mode, services process requests inline. Otherwise, services without
+// Process routines get one, which is stopped when the test finishes.
+//
+// Available since v0.11.0
+func (c *Controller) Add(services ...Service) {
+	for _, service := range services {
+		service.SetRouter(c.ServiceController)
+		c.Register(service)
+		c.mu.Lock()
+		c.services = append(c.services, service)
+		c.mu.Unlock()
+		if c.inline {
+			service.SetInline(true)
+			continue
+		}
+		if service.WorkerCount() == 0 {
+			service.SetWorker(1)
+			c.tb.Cleanup(func() {
+				service.SetWorker(0)
+			})
+		}
+	}
+}
+
+// Wait until all services added to the Controller have processed their requests.
+// The test is marked as failed if timeout elapsed before that.
+//
+// Available since v0.11.0
+func (c *Controller) WaitIdle(timeout time.Duration) bool {
+	c.tb.Helper()
+	c.mu.Lock()
+	idlers := make([]Idler, len(c.services))
+	for i, service := range c.services {
+		idlers[i] = service
+	}
+	c.mu.Unlock()
+	return WaitIdle(c.tb, timeout, idlers...)
+}
+
+// Return all recorded messages in forwarding order.
+//
+// Available since v0.11.0
+func (c *Controller) Dispatches() []*Dispatch {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Dispatch{}, c.records...)
+}
+
+// Return recorded messages forwarded to serviceID in forwarding order.
+//
+// Available since v0.11.0
+func (c *Controller) DispatchesTo(serviceID string) []*Dispatch {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dispatches := []*Dispatch{}
+	for _, dispatch := range c.records {
+		if dispatch.ServiceID == serviceID {
+			dispatches = append(dispatches, dispatch)
+		}
+	}
+	return dispatches
+}
+
+// Clear recorded messages.
+//
+// Available since v0.11.0
+func (c *Controller) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records = nil
+}
+
+// Assert that command was forwarded to serviceID with params.
+// Only keys present in params are compared, nil params matches any params.
+//
+// Available since v0.11.0
+func (c *Controller) AssertDispatched(serviceID, command string, params multiplex.ExecParams) bool {
+	c.tb.Helper()
+	for _, dispatch := range c.DispatchesTo(serviceID) {
+		if dispatch.Command == command && matchParams(dispatch.Params, params) {
+			return true
+		}
+	}
+	c.tb.Errorf("command %q with params %v was not dispatched to %s, dispatched: %s", command, params, serviceID, c.describe(serviceID))
+	return false
+}
+
+// Assert that command was never forwarded to serviceID.
+//
+// Available since v0.11.0
+func (c *Controller) AssertNotDispatched(serviceID, command string) bool {
+	c.tb.Helper()
+	for _, dispatch := range c.DispatchesTo(serviceID) {
+		if dispatch.Command == command {
+			c.tb.Errorf("command %q was dispatched to %s with params %v", command, serviceID, dispatch.Params)
+			return false
+		}
+	}
+	return true
+}
+
+// Assert that command was forwarded to serviceID exactly count times.
+//
+// Available since v0.11.0
+func (c *Controller) AssertDispatchCount(serviceID, command string, count int) bool {
+	c.tb.Helper()
+	actual := 0
+	for _, dispatch := range c.DispatchesTo(serviceID) {
+		if dispatch.Command == command {
+			actual++
+		}
+	}
+	if actual != count {
+		c.tb.Errorf("command %q was dispatched to %s %d times, expected %d", command, serviceID, actual, count)
+		return false
+	}
+	return true
+}
+
+// Assert that exactly the commands were forwarded to serviceID in the same order.
+//
+// Available since v0.11.0
+func (c *Controller) AssertCommands(serviceID string, commands ...string) bool {
+	c.tb.Helper()
+	actual := []string{}
+	for _, dispatch := range c.DispatchesTo(serviceID) {
+		actual = append(actual, dispatch.Command)
+	}
+	if len(commands) == 0 {
+		commands = []string{}
+	}
+	if !reflect.DeepEqual(actual, commands) {
+		c.tb.Errorf("commands dispatched to %s are %q, expected %q", serviceID, actual, commands)
+		return false
+	}
+	return true
+}
+
+// Record the message then deliver it if the target service was added to the Controller.
+func (c *Controller) intercept(serviceID string, msg *multiplex.ServiceMessage, next multiplex.ForwardFunc) error {
+	c.mu.Lock()
+	c.records = append(c.records, &Dispatch{
+		ServiceID: serviceID,
+		Command:   msg.Command,
+		Params:    msg.Params.Clone(),
+	})
+	c.mu.Unlock()
+	service, found := c.Service(serviceID)
+	if !found {
+		return nil
+	}
+	service.Exec(msg.Command, msg.Params)
+	return nil
+}
+
+// Return commands forwarded to serviceID for error messages.
+func (c *Controller) describe(serviceID string) string {
+	dispatches := c.DispatchesTo(serviceID)
+	if len(dispatches) == 0 {
+		return "none"
+	}
+	description := ""
+	for i, dispatch := range dispatches {
+		if i > 0 {
+			description += ", "
+		}
+		description += dispatch.Command
+	}
+	return description
+}
+
+// Return whether actual contains all entries of expected.
+func matchParams(actual, expected multiplex.ExecParams) bool {
+	for key, value := range expected {
+		actualValue, found := actual[key]
+		if !found || !reflect.DeepEqual(actualValue, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Wait until all services have no unprocessed requests.
+// Services must be idle in two consecutive checks, so that messages forwarded between them are accounted.
+// The test is marked as failed if timeout elapsed before that.
+//
+// Available since v0.11.0
+func WaitIdle(tb testing.TB, timeout time.Duration, services ...Idler) bool {
+	tb.Helper()
+	deadline := time.Now().Add(timeout)
+	stable := 0
+	for {
+		busy := ""
+		for _, service := range services {
+			if service.Unprocessed() > 0 {
+				busy = service.ServiceID()
+				break
+			}
+		}
+		if busy == "" {
+			stable++
+			if stable == 2 {
+				return true
+			}
+		} else {
+			stable = 0
+		}
+		if busy != "" && time.Now().After(deadline) {
+			tb.Errorf("service %s is still busy after %v", busy, timeout)
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplextest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+	"github.com/tforce-io/tf-golib/multiplex"
+)
+
+func TestSyncController(t *testing.T) {
+	c := NewSyncController(t)
+	orders := NewOrderService(c.Logger)
+	c.Add(orders)
+
+	orders.Exec("place", multiplex.ExecParams{"id": 1, "email": "a@example.com"})
+	c.AssertCommands("Mailer", "send")
+	c.AssertDispatched("Mailer", "send", multiplex.ExecParams{"to": "a@example.com"})
+	c.AssertDispatchCount("Order", "confirm", 1)
+	c.AssertNotDispatched("Mailer", "cancel")
+	assert.Equal(t, 0, orders.Unprocessed())
+
+	result, err := c.Router().Request("Order", "count", nil, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result)
+
+	c.Reset()
+	assert.Empty(t, c.Dispatches())
+}
+
+func TestController_WaitIdle(t *testing.T) {
+	c := NewController(t)
+	orders := NewOrderService(c.Logger)
+	c.Add(orders)
+	for i := 0; i < 20; i++ {
+		orders.Exec("place", multiplex.ExecParams{"id": i, "email": "a@example.com"})
+	}
+	assert.True(t, c.WaitIdle(time.Second))
+	c.AssertDispatchCount("Mailer", "send", 20)
+	c.AssertDispatchCount("Order", "confirm", 20)
+	assert.Equal(t, 20, orders.confirmed)
+}
+
+func TestController_Assertions(t *testing.T) {
+	c := NewSyncController(t)
+	c.Router().Forward("Mailer", "send", multiplex.ExecParams{"to": "b@example.com"})
+	fake := &failureRecorder{TB: t}
+	c.tb = fake
+	assert.False(t, c.AssertDispatched("Mailer", "send", multiplex.ExecParams{"to": "a@example.com"}))
+	assert.False(t, c.AssertNotDispatched("Mailer", "send"))
+	assert.False(t, c.AssertDispatchCount("Mailer", "send", 2))
+	assert.False(t, c.AssertCommands("Mailer"))
+	assert.Equal(t, 4, fake.failures)
+}
+
+type failureRecorder struct {
+	testing.TB
+	failures int
+}
+
+func (r *failureRecorder) Errorf(format string, args ...interface{}) {
+	r.failures++
+}
+
+type OrderService struct {
+	multiplex.ServiceCore
+	i *multiplex.ServiceCoreInternal
+
+	confirmed int
+}
+
+func NewOrderService(logger diag.Logger) *OrderService {
+	svc := &OrderService{}
+	svc.i = svc.InitServiceCore("Order", logger, svc.coreProcessHook)
+	return svc
+}
+
+func (s *OrderService) coreProcessHook(workerID uint64, msg *multiplex.ServiceMessage) *multiplex.HookState {
+	switch msg.Command {
+	case "place":
+		s.Dispatch("Mailer", "send", multiplex.ExecParams{"to": msg.GetParam("email", "")})
+		s.Dispatch("Order", "confirm", multiplex.ExecParams{"id": msg.GetParam("id", 0)})
+	case "confirm":
+		s.confirmed++
+	case "count":
+		msg.Return(s.confirmed)
+	default:
+		return &multiplex.HookState{Handled: false}
+	}
+	return &multiplex.HookState{Handled: true}
+}
@@ -0,0 +1,12 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package multiplextest provides utilities for testing services built on package multiplex.
+
+Available since v0.11.0
+*/
+package multiplextest
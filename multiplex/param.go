@@ -8,6 +8,31 @@ package multiplex
 
 import (
 	"sync"
+	"sync/atomic"
+
+	"github.com/tforce-io/tf-golib/async"
+)
+
+const (
+	// Parameter key holding the correlation identifier assigned by ServiceRouter to a request.
+	//
+	// Available since v0.11.0
+	CorrelationIDKey = "correlation_id"
+
+	// Parameter key holding the result of a request in a reply message.
+	//
+	// Available since v0.11.0
+	ResultKey = "result"
+
+	// Parameter key holding the error of a request in a reply message.
+	//
+	// Available since v0.11.0
+	ErrorKey = "error"
+
+	// Command of messages delivering results of asynchronous requests.
+	//
+	// Available since v0.11.0
+	ReplyCommand = "reply"
 )
 
 // ServiceMessage defines a request for processing by Service.
@@ -17,6 +42,26 @@ type ServiceMessage struct {
 	Command string
 	Params  ExecParams
 	Extra   interface{}
+
+	// Sequence number in DurableQueue, zero if the message is not persisted.
+	seq uint64
+	// Whether the message is counted as unprocessed by its ServiceCore.
+	tracked bool
+	// Service sending the message via ServiceRouter, empty if sent from outside of services.
+	source string
+	// Idempotency key tracking, nil if the message is not an original request being deduplicated.
+	dedup *dedupEntry
+	// CircuitBreaker which admitted the message, nil if the message was not forwarded through one.
+	circuit *CircuitBreaker
+}
+
+// Return identifier of the service sending the message via ServiceRouter,
+// or empty string if it was sent from outside of services.
+// Source is only known while the message is being forwarded, such as in ForwardInterceptor.
+//
+// Available since v0.11.0
+func (m *ServiceMessage) Source() string {
+	return m.source
 }
 
 // Return parameter value if any, or fallback to def.
@@ -90,6 +135,17 @@ func (m *ServiceMessage) ExpectReturnCustomSignal(signal *sync.WaitGroup) {
 	m.Params.ExpectReturnCustomSignal(signal)
 }
 
+// Indicate that the request expect returning result and return a Future of it.
+// This is for sender side.
+//
+// Available since v0.11.0
+func (m *ServiceMessage) ExpectFuture() *async.Future[interface{}] {
+	if m.Params == nil {
+		m.Params = make(ExecParams)
+	}
+	return m.Params.ExpectFuture()
+}
+
 // Set the returning result then signal listener that the request has been completed.
 // Nothing will be done if the sender doesn't expect returns.
 // This is for recipient side.
@@ -124,6 +180,53 @@ func (m *ServiceMessage) WaitForReturn() interface{} {
 	return nil
 }
 
+// Set the error then signal listener that the request has been completed.
+// Nothing will be done if the sender doesn't expect returns.
+// This is for recipient side.
+//
+// Available since v0.11.0
+func (m *ServiceMessage) ReturnError(err error) {
+	if m.Params != nil {
+		m.Params.ReturnError(err)
+	}
+}
+
+// Return Error of the param.
+//
+// Available since v0.11.0
+func (m *ServiceMessage) ReturnErr() error {
+	if m.Params != nil {
+		return m.Params.ReturnErr()
+	}
+	return nil
+}
+
+// Return correlation identifier of the request, or empty string if it has none.
+//
+// Available since v0.11.0
+func (m *ServiceMessage) CorrelationID() string {
+	if m.Params != nil {
+		return m.Params.CorrelationID()
+	}
+	return ""
+}
+
+// Return whether the message is a reply of an asynchronous request.
+//
+// Available since v0.11.0
+func (m *ServiceMessage) IsReply() bool {
+	return m.Command == ReplyCommand && m.CorrelationID() != ""
+}
+
+// Return result and error delivered by a reply message.
+//
+// Available since v0.11.0
+func (m *ServiceMessage) ReplyResult() (interface{}, error) {
+	result := m.GetParam(ResultKey, nil)
+	err, _ := m.GetParam(ErrorKey, nil).(error)
+	return result, err
+}
+
 // Collection of parameters as key-value mapping.
 //
 // Available since v0.5.0
@@ -153,6 +256,17 @@ func (p ExecParams) Delete(key string) {
 	delete(p, key)
 }
 
+// Return a shallow copy of the params. Nil params result in empty params.
+//
+// Available since v0.11.0
+func (p ExecParams) Clone() ExecParams {
+	clone := make(ExecParams, len(p))
+	for key, val := range p {
+		clone[key] = val
+	}
+	return clone
+}
+
 // Return Singal of the param.
 //
 // Available since v0.5.2
@@ -175,6 +289,25 @@ func (p ExecParams) ReturnResult() interface{} {
 	return nil
 }
 
+// Return Error of the param.
+//
+// Available since v0.11.0
+func (p ExecParams) ReturnErr() error {
+	if p["return"] != nil {
+		ret := p.Get("return", nil).(*ReturnParams)
+		return ret.err
+	}
+	return nil
+}
+
+// Return correlation identifier of the request, or empty string if it has none.
+//
+// Available since v0.11.0
+func (p ExecParams) CorrelationID() string {
+	id, _ := p.Get(CorrelationIDKey, "").(string)
+	return id
+}
+
 // Indicate that the request expect returning result.
 // This is for sender side.
 //
@@ -195,6 +328,21 @@ func (p ExecParams) ExpectReturnCustomSignal(signal *sync.WaitGroup) {
 	}
 }
 
+// Indicate that the request expect returning result and return a Future of it,
+// so the sender doesn't have to wait for the signal.
+// This is for sender side.
+//
+// Available since v0.11.0
+func (p ExecParams) ExpectFuture() *async.Future[interface{}] {
+	promise := async.NewPromise[interface{}]()
+	p.ExpectReturn()
+	ret := p["return"].(*ReturnParams)
+	ret.callback = func(result interface{}, err error) {
+		promise.Complete(result, err)
+	}
+	return promise.Future()
+}
+
 // Set the returning result then signal listener that the request has been completed.
 // Nothing will be done if the sender doesn't expect returns.
 // This is for recipient side.
@@ -203,8 +351,19 @@ func (p ExecParams) ExpectReturnCustomSignal(signal *sync.WaitGroup) {
 func (p ExecParams) Return(result interface{}) {
 	if p["return"] != nil {
 		ret := p.Get("return", nil).(*ReturnParams)
-		ret.result = result
-		ret.signal.Done()
+		ret.complete(result, nil)
+	}
+}
+
+// Set the error then signal listener that the request has been completed.
+// Nothing will be done if the sender doesn't expect returns.
+// This is for recipient side.
+//
+// Available since v0.11.0
+func (p ExecParams) ReturnError(err error) {
+	if p["return"] != nil {
+		ret := p.Get("return", nil).(*ReturnParams)
+		ret.complete(nil, err)
 	}
 }
 
@@ -238,8 +397,11 @@ func (p ExecParams) WaitForReturn() interface{} {
 //
 // Available since v0.5.0
 type ReturnParams struct {
-	signal *sync.WaitGroup
-	result interface{}
+	completed uint32
+	signal    *sync.WaitGroup
+	result    interface{}
+	err       error
+	callback  func(result interface{}, err error)
 }
 
 // Return Singal of the param.
@@ -255,3 +417,24 @@ func (p *ReturnParams) Signal() *sync.WaitGroup {
 func (p *ReturnParams) Result() interface{} {
 	return p.result
 }
+
+// Return Error of the param.
+//
+// Available since v0.11.0
+func (p *ReturnParams) Err() error {
+	return p.err
+}
+
+// Store the outcome, notify the callback if any then signal listener.
+// Only the first outcome is kept.
+func (p *ReturnParams) complete(result interface{}, err error) {
+	if !atomic.CompareAndSwapUint32(&p.completed, 0, 1) {
+		return
+	}
+	p.result = result
+	p.err = err
+	if p.callback != nil {
+		p.callback(result, err)
+	}
+	p.signal.Done()
+}
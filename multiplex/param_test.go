@@ -7,6 +7,8 @@
 package multiplex
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"testing"
 
@@ -74,6 +76,32 @@ func TestServiceMessage_Return(t *testing.T) {
 	assert.Equal(t, "result", msg.WaitForReturn(), "Return should set the result and signal completion")
 }
 
+func TestServiceMessage_ReturnError(t *testing.T) {
+	msg := ServiceMessage{}
+	assert.Nil(t, msg.ReturnErr(), "ReturnErr should return nil if no return is expected")
+	msg.ExpectReturn()
+	go func() {
+		msg.ReturnError(errors.New("failed"))
+	}()
+	assert.Nil(t, msg.WaitForReturn(), "ReturnError should signal completion without result")
+	assert.EqualError(t, msg.ReturnErr(), "failed", "ReturnErr should return the error")
+}
+
+func TestServiceMessage_CorrelationID(t *testing.T) {
+	msg := ServiceMessage{}
+	assert.Equal(t, "", msg.CorrelationID(), "CorrelationID should be empty without params")
+	assert.False(t, msg.IsReply())
+	msg = ServiceMessage{
+		Command: ReplyCommand,
+		Params:  ExecParams{CorrelationIDKey: "7", ResultKey: "result"},
+	}
+	assert.Equal(t, "7", msg.CorrelationID(), "CorrelationID should match")
+	assert.True(t, msg.IsReply())
+	result, err := msg.ReplyResult()
+	assert.Equal(t, "result", result)
+	assert.Nil(t, err)
+}
+
 func TestServiceMessage_WaitForReturn_NoReturn(t *testing.T) {
 	msg := ServiceMessage{}
 	assert.Nil(t, msg.WaitForReturn(), "WaitForReturn should return nil if no return is expected")
@@ -161,6 +189,24 @@ func TestExecParams_Return(t *testing.T) {
 	assert.Equal(t, "result", params.WaitForReturn(), "Return should set the result and signal completion")
 }
 
+func TestExecParams_ExpectFuture(t *testing.T) {
+	params := ExecParams{}
+	future := params.ExpectFuture()
+	go func() {
+		params.Return("result")
+	}()
+	result, err := future.Await(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "result", result, "Future should receive the result")
+	assert.Equal(t, "result", params.WaitForReturn(), "signal must still be completed")
+
+	msg := &ServiceMessage{}
+	future = msg.ExpectFuture()
+	msg.ReturnError(errors.New("failed"))
+	_, err = future.Await(context.Background())
+	assert.EqualError(t, err, "failed")
+}
+
 func TestExecParams_WaitForReturn_NoReturn(t *testing.T) {
 	params := ExecParams{}
 	assert.Nil(t, params.WaitForReturn(), "WaitForReturn should return nil if no return is expected")
@@ -0,0 +1,218 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a request is rejected by a RateLimiter.
+//
+// Available since v0.11.0
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrInvalidRate is returned when a RateLimiter is created with a non-positive rate.
+//
+// Available since v0.11.0
+var ErrInvalidRate = errors.New("rate must be positive")
+
+// RateLimitMode defines how a rate limited request is handled.
+//
+// Available since v0.11.0
+type RateLimitMode int8
+
+const (
+	// Wait until the request is allowed.
+	RateLimitWait RateLimitMode = iota
+
+	// Reject the request with ErrRateLimited.
+	RateLimitReject
+)
+
+// RateLimiter is a token bucket allowing rate requests per second on average
+// and bursts of up to burst requests. It is safe for concurrent use.
+//
+// Available since v0.11.0
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+
+	allowed   uint64
+	delayed   uint64
+	rejected  uint64
+	cancelled uint64
+	waited    time.Duration
+}
+
+// RateLimiterStats is a snapshot of RateLimiter metrics.
+//
+// Available since v0.11.0
+type RateLimiterStats struct {
+	// Number of requests allowed, including delayed ones.
+	Allowed uint64
+	// Number of requests that had to wait for a token.
+	Delayed uint64
+	// Number of requests rejected.
+	Rejected uint64
+	// Number of waits abandoned because their context was done.
+	Cancelled uint64
+	// Total time requests waited for tokens.
+	Waited time.Duration
+}
+
+// Return number of requests throttled, either delayed, rejected or cancelled while waiting.
+//
+// Available since v0.11.0
+func (s RateLimiterStats) Throttled() uint64 {
+	return s.Delayed + s.Rejected + s.Cancelled
+}
+
+// Return new RateLimiter with a full bucket. Burst is at least 1.
+// Return ErrInvalidRate if rate is not positive.
+//
+// Available since v0.11.0
+func NewRateLimiter(rate float64, burst int) (*RateLimiter, error) {
+	if rate <= 0 {
+		return nil, ErrInvalidRate
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}, nil
+}
+
+// Take a token if available. Return false without waiting otherwise.
+//
+// Available since v0.11.0
+func (l *RateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens < 1 {
+		l.rejected++
+		return false
+	}
+	l.tokens--
+	l.allowed++
+	return true
+}
+
+// Wait until a token is available then take it.
+// Return the error of ctx if it is done before that, in which case no token is taken.
+//
+// Available since v0.11.0
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.refill()
+	l.tokens--
+	if l.tokens >= 0 {
+		l.allowed++
+		l.mu.Unlock()
+		return nil
+	}
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		l.mu.Lock()
+		l.allowed++
+		l.delayed++
+		l.waited += delay
+		l.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.refill()
+		l.tokens++
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.cancelled++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Return number of tokens currently available.
+//
+// Available since v0.11.0
+func (l *RateLimiter) Tokens() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	return l.tokens
+}
+
+// Return a snapshot of metrics.
+//
+// Available since v0.11.0
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return RateLimiterStats{
+		Allowed:   l.allowed,
+		Delayed:   l.delayed,
+		Rejected:  l.rejected,
+		Cancelled: l.cancelled,
+		Waited:    l.waited,
+	}
+}
+
+// Add tokens accumulated since last refill. Must be called with lock held.
+func (l *RateLimiter) refill() {
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+// Return an Interceptor limiting the rate of messages processed by a ServiceCore.
+// If commands are specified, only messages with these commands are limited.
+// In RateLimitReject mode, rejected messages return ErrRateLimited to the sender.
+// The exit request is never limited.
+//
+// Available since v0.11.0
+func RateLimitInterceptor(limiter *RateLimiter, mode RateLimitMode, commands ...string) Interceptor {
+	limited := make(map[string]bool, len(commands))
+	for _, command := range commands {
+		limited[command] = true
+	}
+	return func(ctx *ProcessContext, msg *ServiceMessage, next ProcessFunc) *HookState {
+		if len(limited) > 0 && !limited[msg.Command] {
+			return next(ctx, msg)
+		}
+		if mode == RateLimitReject {
+			if !limiter.Allow() {
+				msg.ReturnError(ErrRateLimited)
+				return &HookState{Handled: true, Error: ErrRateLimited}
+			}
+		} else if err := limiter.Wait(context.Background()); err != nil {
+			msg.ReturnError(err)
+			return &HookState{Handled: true, Error: err}
+		}
+		return next(ctx, msg)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter, _ := NewRateLimiter(2, 3)
+	limiter.now = func() time.Time { return now }
+	for i := 0; i < 3; i++ {
+		assert.True(t, limiter.Allow(), "burst %d", i)
+	}
+	assert.False(t, limiter.Allow())
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, limiter.Allow())
+	assert.False(t, limiter.Allow())
+	now = now.Add(time.Hour)
+	assert.Equal(t, float64(3), limiter.Tokens())
+
+	stats := limiter.Stats()
+	assert.Equal(t, uint64(4), stats.Allowed)
+	assert.Equal(t, uint64(2), stats.Rejected)
+	assert.Equal(t, uint64(2), stats.Throttled())
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter, _ := NewRateLimiter(100, 1)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+	stats := limiter.Stats()
+	assert.Equal(t, uint64(3), stats.Allowed)
+	assert.Equal(t, uint64(2), stats.Delayed)
+	assert.Greater(t, stats.Waited, time.Duration(0))
+
+	limiter, _ = NewRateLimiter(0.1, 1)
+	assert.True(t, limiter.Allow())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.True(t, errors.Is(limiter.Wait(ctx), context.DeadlineExceeded))
+	assert.Less(t, limiter.Tokens(), float64(1))
+	assert.Greater(t, limiter.Tokens(), float64(-0.1), "token of canceled wait must be returned")
+	stats = limiter.Stats()
+	assert.Equal(t, uint64(1), stats.Cancelled)
+	assert.Equal(t, uint64(0), stats.Rejected, "canceled wait must not be counted as rejected")
+	assert.Equal(t, uint64(1), stats.Throttled())
+}
+
+func TestRateLimiter_Wait_CancelKeepsBurst(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var elapsed int64
+	limiter, _ := NewRateLimiter(0.01, 1)
+	limiter.now = func() time.Time { return start.Add(time.Duration(atomic.LoadInt64(&elapsed))) }
+	assert.True(t, limiter.Allow())
+	ctx, cancel := context.WithCancel(context.Background())
+	waited := make(chan error, 1)
+	go func() {
+		waited <- limiter.Wait(ctx)
+	}()
+	assert.True(t, waitCondition(func() bool { return limiter.Tokens() < 0 }))
+	atomic.StoreInt64(&elapsed, int64(time.Hour))
+	assert.Equal(t, float64(1), limiter.Tokens())
+	cancel()
+	assert.ErrorIs(t, <-waited, context.Canceled)
+	assert.Equal(t, float64(1), limiter.Tokens(), "refund must not exceed burst")
+}
+
+func TestNewRateLimiter_InvalidRate(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		limiter, err := NewRateLimiter(rate, 1)
+		assert.Nil(t, limiter)
+		assert.ErrorIs(t, err, ErrInvalidRate)
+	}
+}
+
+func TestRateLimitInterceptor_Reject(t *testing.T) {
+	svc := newCommandService()
+	limiter, _ := NewRateLimiter(0.001, 2)
+	svc.Use(RateLimitInterceptor(limiter, RateLimitReject, "echo"))
+	svc.SetWorker(1)
+	errs := []error{}
+	for i := 0; i < 4; i++ {
+		params := ExecParams{}
+		params.ExpectReturn()
+		svc.Exec("echo", params)
+		params.Wait()
+		errs = append(errs, params.ReturnErr())
+	}
+	assert.Equal(t, []error{nil, nil, ErrRateLimited, ErrRateLimited}, errs)
+	params := ExecParams{}
+	params.ExpectReturn()
+	svc.Exec("other", params)
+	params.Wait()
+	assert.NoError(t, params.ReturnErr(), "commands not listed must not be limited")
+	assert.Equal(t, uint64(2), limiter.Stats().Rejected)
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second), "exit must not be limited")
+}
+
+func TestRateLimitInterceptor_Wait(t *testing.T) {
+	svc := newCommandService()
+	limiter, _ := NewRateLimiter(100, 1)
+	svc.Use(RateLimitInterceptor(limiter, RateLimitWait))
+	svc.SetWorker(1)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		params := ExecParams{}
+		params.ExpectReturn()
+		svc.Exec("echo", params)
+		params.Wait()
+		assert.NoError(t, params.ReturnErr())
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+	assert.Equal(t, uint64(2), limiter.Stats().Delayed)
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func newCommandService() *IdleService {
+	svc := NewIdleService(diag.NewDebugLogger(10))
+	svc.i.CoreProcessHook = func(workerID uint64, msg *ServiceMessage) *HookState {
+		if msg.Command == "exit" {
+			return &HookState{Handled: false}
+		}
+		msg.Return(msg.Command)
+		return &HookState{Handled: true}
+	}
+	return svc
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/tforce-io/tf-golib/async"
+)
+
+// pendingRequest tracks a request waiting for its result.
+type pendingRequest struct {
+	serviceID string
+	command   string
+	timer     *time.Timer
+	deliver   func(correlationID string, result interface{}, err error)
+}
+
+// Forward the request to the specified serviceID and wait for its result.
+// RequestTimeoutError is returned if the service doesn't return within timeout.
+// Non-positive timeout means waiting indefinitely.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) Request(serviceID, command string, params ExecParams, timeout time.Duration) (interface{}, error) {
+	return s.request("", serviceID, command, params, timeout)
+}
+
+// Forward the request sent by source service and wait for its result.
+func (s *ServiceRouter) request(source, serviceID, command string, params ExecParams, timeout time.Duration) (interface{}, error) {
+	return s.requestFuture(source, serviceID, command, params, timeout).Await(context.Background())
+}
+
+// Forward the request to the specified serviceID and return a Future of its result.
+// RequestTimeoutError is delivered if the service doesn't return within timeout.
+// Non-positive timeout means waiting indefinitely.
+// Cancelling the Future stops tracking the request, its result will be discarded.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) RequestFuture(serviceID, command string, params ExecParams, timeout time.Duration) *async.Future[interface{}] {
+	return s.requestFuture("", serviceID, command, params, timeout)
+}
+
+// Forward the request sent by source service and return a Future of its result.
+func (s *ServiceRouter) requestFuture(source, serviceID, command string, params ExecParams, timeout time.Duration) *async.Future[interface{}] {
+	promise := async.NewPromise[interface{}]()
+	params = s.begin(serviceID, command, params, timeout, func(_ string, result interface{}, err error) {
+		promise.Complete(result, err)
+	})
+	correlationID := params.CorrelationID()
+	promise.OnCancel(func() {
+		s.finish(correlationID, nil, context.Canceled)
+	})
+	if err := s.forward(source, serviceID, command, params); err != nil {
+		s.finish(correlationID, nil, err)
+	}
+	return promise.Future()
+}
+
+// Forward the request to the specified serviceID and return its correlation ID immediately.
+// The result will be delivered to replyTo service as a message with ReplyCommand,
+// the same correlation ID, the result under ResultKey and the error if any under ErrorKey.
+// RequestTimeoutError is delivered if the service doesn't return within timeout.
+// Non-positive timeout means waiting indefinitely.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) RequestAsync(replyTo, serviceID, command string, params ExecParams, timeout time.Duration) string {
+	params = s.begin(serviceID, command, params, timeout, func(correlationID string, result interface{}, err error) {
+		replyParams := ExecParams{
+			CorrelationIDKey: correlationID,
+			ResultKey:        result,
+		}
+		if err != nil {
+			replyParams[ErrorKey] = err
+		}
+		if err := s.forward(serviceID, replyTo, ReplyCommand, replyParams); err != nil {
+			s.c.i.Logger.Errorf(err, "Router: Reply to request %s from %s can't be delivered to %s.", correlationID, serviceID, replyTo)
+		}
+	})
+	correlationID := params.CorrelationID()
+	if err := s.forward(replyTo, serviceID, command, params); err != nil {
+		s.finish(correlationID, nil, err)
+	}
+	return correlationID
+}
+
+// Return number of requests still waiting for their results.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) PendingRequests() int {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	return len(s.pending)
+}
+
+// Assign a correlation ID to the request and track it until its result is delivered.
+// A callback already registered on the ReturnParams of the request is still called, before delivery.
+func (s *ServiceRouter) begin(serviceID, command string, params ExecParams, timeout time.Duration, deliver func(correlationID string, result interface{}, err error)) ExecParams {
+	if params == nil {
+		params = make(ExecParams)
+	}
+	correlationID := strconv.FormatUint(atomic.AddUint64(&s.lastRequestID, 1), 10)
+	params[CorrelationIDKey] = correlationID
+	if params["return"] == nil {
+		params.ExpectReturn()
+	}
+	req := &pendingRequest{
+		serviceID: serviceID,
+		command:   command,
+		deliver:   deliver,
+	}
+	s.pendingMu.Lock()
+	s.pending[correlationID] = req
+	if timeout > 0 {
+		req.timer = time.AfterFunc(timeout, func() {
+			s.finish(correlationID, nil, &RequestTimeoutError{
+				ServiceID:     serviceID,
+				Command:       command,
+				CorrelationID: correlationID,
+				Timeout:       timeout,
+			})
+		})
+	}
+	s.pendingMu.Unlock()
+	ret := params["return"].(*ReturnParams)
+	previous := ret.callback
+	ret.callback = func(result interface{}, err error) {
+		if previous != nil {
+			previous(result, err)
+		}
+		s.finish(correlationID, result, err)
+	}
+	return params
+}
+
+// Deliver the outcome of the request exactly once.
+func (s *ServiceRouter) finish(correlationID string, result interface{}, err error) {
+	s.pendingMu.Lock()
+	req, ok := s.pending[correlationID]
+	if ok {
+		delete(s.pending, correlationID)
+	}
+	s.pendingMu.Unlock()
+	if !ok {
+		return
+	}
+	if req.timer != nil {
+		req.timer.Stop()
+	}
+	req.deliver(correlationID, result, err)
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestServiceRouter_Request(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	random := NewRandomService(logger)
+	random.SetRouter(controller)
+	random.SetWorker(1)
+	controller.Register(random)
+	controller.Run(false)
+	result, err := controller.Router().Request("Random", "", ExecParams{}, time.Second)
+	assert.NoError(t, err)
+	assert.Len(t, result, 32, "invalid result")
+	assert.Equal(t, 0, controller.Router().PendingRequests(), "request must be completed")
+}
+
+func TestServiceRouter_Request_Timeout(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	idle := NewIdleService(logger)
+	idle.SetRouter(controller)
+	idle.SetWorker(1)
+	controller.Register(idle)
+	controller.Run(false)
+	params := ExecParams{}
+	result, err := controller.Router().Request("Idle", "noop", params, 20*time.Millisecond)
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, ErrRequestTimeout), "must fail with timeout")
+	var timeoutErr *RequestTimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, "Idle", timeoutErr.ServiceID)
+	assert.Equal(t, "noop", timeoutErr.Command)
+	assert.Equal(t, params.CorrelationID(), timeoutErr.CorrelationID)
+	assert.Equal(t, 0, controller.Router().PendingRequests(), "request must be discarded")
+	params.Return("late")
+}
+
+func TestServiceRouter_Request_Error(t *testing.T) {
+	params := ExecParams{}
+	params.ExpectReturn()
+	params.ReturnError(errors.New("failed"))
+	assert.EqualError(t, params.ReturnErr(), "failed")
+	assert.Nil(t, params.ReturnResult())
+}
+
+func TestServiceRouter_RequestAsync(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	random := NewRandomService(logger)
+	random.SetRouter(controller)
+	random.SetWorker(1)
+	controller.Register(random)
+	idle := NewIdleService(logger)
+	idle.SetRouter(controller)
+	controller.Register(idle)
+	replies := NewReplyService(logger)
+	replies.SetRouter(controller)
+	replies.SetWorker(1)
+	controller.Register(replies)
+	controller.Run(false)
+
+	id1 := replies.RequestAsync("Random", "", nil, time.Second)
+	id2 := replies.RequestAsync("Idle", "noop", nil, 20*time.Millisecond)
+	assert.NotEqual(t, id1, id2, "correlation IDs must be unique")
+	received := map[string]*ServiceMessage{}
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-replies.replyChan:
+			received[msg.CorrelationID()] = msg
+		case <-time.After(time.Second):
+			t.Fatal("reply not received")
+		}
+	}
+	result, err := received[id1].ReplyResult()
+	assert.NoError(t, err)
+	assert.Len(t, result, 32, "invalid result")
+	result, err = received[id2].ReplyResult()
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, ErrRequestTimeout), "must fail with timeout")
+}
+
+func TestServiceRouter_RequestAsync_Timeout(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	idle := NewIdleService(logger)
+	idle.SetRouter(controller)
+	controller.Register(idle)
+	replies := NewReplyService(logger)
+	replies.SetRouter(controller)
+	replies.SetWorker(1)
+	controller.Register(replies)
+	controller.Run(false)
+
+	for i := 0; i < 20; i++ {
+		id := replies.RequestAsync("Idle", "noop", nil, time.Nanosecond)
+		select {
+		case msg := <-replies.replyChan:
+			assert.Equal(t, id, msg.CorrelationID(), "reply must carry the correlation ID of the request")
+			_, err := msg.ReplyResult()
+			assert.True(t, errors.Is(err, ErrRequestTimeout), "must fail with timeout")
+		case <-time.After(time.Second):
+			t.Fatal("reply not received")
+		}
+	}
+}
+
+func TestServiceRouter_RequestFuture(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	random := NewRandomService(logger)
+	random.SetRouter(controller)
+	random.SetWorker(1)
+	controller.Register(random)
+	idle := NewIdleService(logger)
+	idle.SetRouter(controller)
+	controller.Register(idle)
+	controller.Run(false)
+
+	future := controller.Router().RequestFuture("Random", "", nil, time.Second)
+	result, err := future.Await(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, result, 32, "invalid result")
+
+	future = idle.RequestFuture("Idle", "noop", nil, 20*time.Millisecond)
+	_, err = future.Await(context.Background())
+	assert.True(t, errors.Is(err, ErrRequestTimeout), "must fail with timeout")
+
+	params := ExecParams{}
+	future = controller.Router().RequestFuture("Idle", "noop", params, 0)
+	assert.Equal(t, 1, controller.Router().PendingRequests())
+	assert.True(t, future.Cancel())
+	_, err = future.Await(context.Background())
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, controller.Router().PendingRequests(), "cancelled request must be discarded")
+	params.Return("late")
+}
+
+type ReplyService struct {
+	ServiceCore
+	i         *ServiceCoreInternal
+	replyChan chan *ServiceMessage
+}
+
+func NewReplyService(logger diag.Logger) *ReplyService {
+	svc := &ReplyService{
+		replyChan: make(chan *ServiceMessage, 16),
+	}
+	svc.i = svc.InitServiceCore("Reply", logger, svc.coreProcessHook)
+	return svc
+}
+
+func (s *ReplyService) coreProcessHook(workerID uint64, msg *ServiceMessage) *HookState {
+	if msg.IsReply() {
+		s.replyChan <- msg
+		return &HookState{Handled: true}
+	}
+	return &HookState{Handled: false}
+}
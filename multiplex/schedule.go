@@ -0,0 +1,375 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrInvalidInterval is returned when scheduling a recurring message with a non-positive interval.
+//
+// Available since v0.11.0
+var ErrInvalidInterval = errors.New("interval must be positive")
+
+// ErrNoRouter is returned when a service without Router schedules a request.
+//
+// Available since v0.11.0
+var ErrNoRouter = errors.New("router not set")
+
+// Schedule is a handle of a delayed or recurring message.
+//
+// Available since v0.11.0
+type Schedule struct {
+	id        string
+	serviceID string
+	command   string
+	params    ExecParams
+	deliver   func(params ExecParams)
+
+	interval time.Duration
+	cron     *CronSchedule
+
+	scheduler *scheduler
+	timer     *time.Timer
+	next      time.Time
+	runs      uint64
+	done      bool
+}
+
+// ScheduleInfo is a snapshot of a pending Schedule.
+//
+// Available since v0.11.0
+type ScheduleInfo struct {
+	ID        string
+	ServiceID string
+	Command   string
+	Next      time.Time
+	Recurring bool
+	Runs      uint64
+}
+
+// scheduler keeps track of all pending schedules of a controller.
+type scheduler struct {
+	mu        sync.Mutex
+	lastID    uint64
+	schedules map[string]*Schedule
+	now       func() time.Time
+}
+
+// Return new empty scheduler.
+func newScheduler() *scheduler {
+	return &scheduler{
+		schedules: make(map[string]*Schedule),
+		now:       time.Now,
+	}
+}
+
+// Return identifier of the Schedule.
+//
+// Available since v0.11.0
+func (h *Schedule) ID() string {
+	return h.id
+}
+
+// Return the next time the message will be delivered.
+// Zero time is returned if the Schedule is completed or canceled.
+//
+// Available since v0.11.0
+func (h *Schedule) Next() time.Time {
+	h.scheduler.mu.Lock()
+	defer h.scheduler.mu.Unlock()
+	return h.next
+}
+
+// Return number of times the message was delivered.
+//
+// Available since v0.11.0
+func (h *Schedule) Runs() uint64 {
+	h.scheduler.mu.Lock()
+	defer h.scheduler.mu.Unlock()
+	return h.runs
+}
+
+// Cancel the Schedule. Return false if it was already completed or canceled.
+//
+// Available since v0.11.0
+func (h *Schedule) Cancel() bool {
+	return h.scheduler.cancel(h.id)
+}
+
+// Forward the message to the specified serviceID after the delay.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) ForwardAfter(delay time.Duration, serviceID, command string, params ExecParams) *Schedule {
+	return s.ForwardAt(s.c.scheduler.now().Add(delay), serviceID, command, params)
+}
+
+// Forward the message to the specified serviceID at the specified time.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) ForwardAt(at time.Time, serviceID, command string, params ExecParams) *Schedule {
+	h := s.newSchedule(serviceID, command, params)
+	s.c.scheduler.add(h, at)
+	return h
+}
+
+// Forward the message to the specified serviceID repeatedly with the interval, starting after one interval.
+// Each delivery receives its own copy of params. ErrInvalidInterval is returned if interval is not positive.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) ForwardEvery(interval time.Duration, serviceID, command string, params ExecParams) (*Schedule, error) {
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	h := s.newSchedule(serviceID, command, params)
+	h.interval = interval
+	s.c.scheduler.add(h, s.c.scheduler.now().Add(interval))
+	return h, nil
+}
+
+// Forward the message to the specified serviceID at every time matching the cron expression.
+// Each delivery receives its own copy of params. See CronSchedule for supported syntax.
+// ErrInvalidCron is returned if the expression is malformed or never matches.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) ForwardCron(spec string, serviceID, command string, params ExecParams) (*Schedule, error) {
+	cron, next, err := parseScheduleCron(spec, s.c.scheduler.now())
+	if err != nil {
+		return nil, err
+	}
+	h := s.newSchedule(serviceID, command, params)
+	h.cron = cron
+	s.c.scheduler.add(h, next)
+	return h, nil
+}
+
+// Enqueue the request to this service after the delay.
+// The Schedule is managed by the controller of configurated Router, ErrNoRouter is returned if there is none.
+//
+// Available since v0.11.0
+func (s ServiceCore) ExecAfter(delay time.Duration, command string, params ExecParams) (*Schedule, error) {
+	if s.i.Router == nil {
+		return nil, ErrNoRouter
+	}
+	return s.ExecAt(s.i.Router.c.scheduler.now().Add(delay), command, params)
+}
+
+// Enqueue the request to this service at the specified time.
+// The Schedule is managed by the controller of configurated Router, ErrNoRouter is returned if there is none.
+//
+// Available since v0.11.0
+func (s ServiceCore) ExecAt(at time.Time, command string, params ExecParams) (*Schedule, error) {
+	if s.i.Router == nil {
+		return nil, ErrNoRouter
+	}
+	h := s.newSchedule(command, params)
+	s.i.Router.c.scheduler.add(h, at)
+	return h, nil
+}
+
+// Enqueue the request to this service repeatedly with the interval, starting after one interval.
+// The Schedule is managed by the controller of configurated Router, ErrNoRouter is returned if there is none.
+// ErrInvalidInterval is returned if interval is not positive.
+//
+// Available since v0.11.0
+func (s ServiceCore) ExecEvery(interval time.Duration, command string, params ExecParams) (*Schedule, error) {
+	if s.i.Router == nil {
+		return nil, ErrNoRouter
+	}
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	h := s.newSchedule(command, params)
+	h.interval = interval
+	sched := s.i.Router.c.scheduler
+	sched.add(h, sched.now().Add(interval))
+	return h, nil
+}
+
+// Enqueue the request to this service at every time matching the cron expression.
+// The Schedule is managed by the controller of configurated Router, ErrNoRouter is returned if there is none.
+// ErrInvalidCron is returned if the expression is malformed or never matches.
+//
+// Available since v0.11.0
+func (s ServiceCore) ExecCron(spec string, command string, params ExecParams) (*Schedule, error) {
+	if s.i.Router == nil {
+		return nil, ErrNoRouter
+	}
+	sched := s.i.Router.c.scheduler
+	cron, next, err := parseScheduleCron(spec, sched.now())
+	if err != nil {
+		return nil, err
+	}
+	h := s.newSchedule(command, params)
+	h.cron = cron
+	sched.add(h, next)
+	return h, nil
+}
+
+// Return all pending schedules ordered by their next delivery time.
+//
+// Available since v0.11.0
+func (s *ServiceController) Schedules() []*ScheduleInfo {
+	s.scheduler.mu.Lock()
+	defer s.scheduler.mu.Unlock()
+	infos := make([]*ScheduleInfo, 0, len(s.scheduler.schedules))
+	for _, h := range s.scheduler.schedules {
+		infos = append(infos, &ScheduleInfo{
+			ID:        h.id,
+			ServiceID: h.serviceID,
+			Command:   h.command,
+			Next:      h.next,
+			Recurring: h.interval > 0 || h.cron != nil,
+			Runs:      h.runs,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Next.Before(infos[j].Next)
+	})
+	return infos
+}
+
+// Cancel a pending schedule by its ID. Return false if it is not found.
+//
+// Available since v0.11.0
+func (s *ServiceController) CancelSchedule(id string) bool {
+	return s.scheduler.cancel(id)
+}
+
+// Parse the cron expression and return its first matching time after now.
+func parseScheduleCron(spec string, now time.Time) (*CronSchedule, time.Time, error) {
+	cron, err := ParseCron(spec)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	next := cron.Next(now)
+	if next.IsZero() {
+		return nil, time.Time{}, fmt.Errorf("%w: %q never matches", ErrInvalidCron, spec)
+	}
+	return cron, next, nil
+}
+
+// Return new Schedule delivering the message through the router.
+func (s *ServiceRouter) newSchedule(serviceID, command string, params ExecParams) *Schedule {
+	return &Schedule{
+		serviceID: serviceID,
+		command:   command,
+		params:    params,
+		deliver: func(params ExecParams) {
+			if err := s.ForwardE(serviceID, command, params); err != nil {
+				s.c.i.Logger.Errorf(err, "Router: Scheduled command %q to %s failed.", command, serviceID)
+			}
+		},
+	}
+}
+
+// Return new Schedule delivering the message directly to this service, recording it in the Journal if any.
+func (s ServiceCore) newSchedule(command string, params ExecParams) *Schedule {
+	return &Schedule{
+		serviceID: s.i.ServiceID,
+		command:   command,
+		params:    params,
+		deliver: func(params ExecParams) {
+			msg := &ServiceMessage{
+				Command: command,
+				Params:  params,
+				source:  s.i.ServiceID,
+			}
+			s.i.Router.journaled(s.i.ServiceID, msg, func() error {
+				s.Exec(command, params)
+				return nil
+			})
+		},
+	}
+}
+
+// Register the Schedule and arm its timer for the first delivery.
+func (c *scheduler) add(h *Schedule, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastID++
+	h.id = strconv.FormatUint(c.lastID, 10)
+	h.scheduler = c
+	c.schedules[h.id] = h
+	c.arm(h, at)
+}
+
+// Arm the timer of the Schedule. Must be called with lock held.
+func (c *scheduler) arm(h *Schedule, at time.Time) {
+	if at.IsZero() {
+		c.remove(h)
+		return
+	}
+	h.next = at
+	delay := at.Sub(c.now())
+	if h.timer == nil {
+		h.timer = time.AfterFunc(delay, func() { c.fire(h) })
+	} else {
+		h.timer.Reset(delay)
+	}
+}
+
+// Deliver the message then re-arm the timer of recurring Schedule.
+func (c *scheduler) fire(h *Schedule) {
+	c.mu.Lock()
+	if h.done {
+		c.mu.Unlock()
+		return
+	}
+	h.runs++
+	params := h.params
+	recurring := h.interval > 0 || h.cron != nil
+	if recurring {
+		params = h.params.Clone()
+		if h.interval > 0 {
+			c.arm(h, h.next.Add(h.interval))
+		} else {
+			c.arm(h, h.cron.Next(c.now()))
+		}
+	} else {
+		c.remove(h)
+	}
+	c.mu.Unlock()
+	h.deliver(params)
+}
+
+// Cancel the Schedule by its ID.
+func (c *scheduler) cancel(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, found := c.schedules[id]
+	if !found {
+		return false
+	}
+	h.timer.Stop()
+	c.remove(h)
+	return true
+}
+
+// Cancel all schedules delivering to the service.
+func (c *scheduler) cancelService(serviceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, h := range c.schedules {
+		if h.serviceID == serviceID {
+			h.timer.Stop()
+			c.remove(h)
+		}
+	}
+}
+
+// Remove the Schedule from pending list. Must be called with lock held.
+func (c *scheduler) remove(h *Schedule) {
+	h.done = true
+	h.next = time.Time{}
+	delete(c.schedules, h.id)
+}
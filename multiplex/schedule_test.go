@@ -0,0 +1,140 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestServiceRouter_ForwardAfter(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	collector := NewCollectorService("Collector", logger)
+	collector.SetRouter(controller)
+	collector.SetWorker(1)
+	controller.Register(collector)
+	controller.Run(false)
+	start := time.Now()
+	h := controller.Router().ForwardAfter(30*time.Millisecond, "Collector", "delayed", ExecParams{"key": "value"})
+	assert.NotEmpty(t, h.ID())
+	assert.False(t, h.Next().IsZero())
+	msg := collector.Receive(t)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond, "message must be delayed")
+	assert.Equal(t, "delayed", msg.Command)
+	assert.Equal(t, "value", msg.GetParam("key", ""))
+	assert.Equal(t, uint64(1), h.Runs())
+	assert.True(t, h.Next().IsZero(), "one-shot schedule must be completed")
+	assert.False(t, h.Cancel(), "completed schedule can't be canceled")
+	assert.Empty(t, controller.Schedules())
+}
+
+func TestServiceCore_ExecAt_Cancel(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	collector := NewCollectorService("Collector", logger)
+	collector.SetRouter(controller)
+	collector.SetWorker(1)
+	h1, err := collector.ExecAt(time.Now().Add(time.Hour), "later", nil)
+	assert.NoError(t, err)
+	h2, err := collector.ExecAfter(20*time.Millisecond, "sooner", nil)
+	assert.NoError(t, err)
+	schedules := controller.Schedules()
+	assert.Len(t, schedules, 2)
+	assert.Equal(t, h2.ID(), schedules[0].ID, "schedules must be ordered by next time")
+	assert.Equal(t, "Collector", schedules[0].ServiceID)
+	assert.Equal(t, "sooner", schedules[0].Command)
+	assert.False(t, schedules[0].Recurring)
+	assert.True(t, controller.CancelSchedule(h1.ID()))
+	assert.False(t, h1.Cancel(), "schedule must be canceled only once")
+	assert.Equal(t, "sooner", collector.Receive(t).Command)
+	select {
+	case msg := <-collector.received:
+		t.Fatalf("unexpected message %q", msg.Command)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestServiceCore_ExecEvery(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	collector := NewCollectorService("Collector", logger)
+	collector.SetRouter(controller)
+	collector.SetWorker(1)
+	params := ExecParams{"count": 0}
+	h, err := collector.ExecEvery(10*time.Millisecond, "tick", params)
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		msg := collector.Receive(t)
+		assert.Equal(t, "tick", msg.Command)
+		msg.SetParam("count", i+1)
+	}
+	assert.Equal(t, 0, params["count"], "each delivery must receive its own params")
+	assert.True(t, controller.Schedules()[0].Recurring)
+	assert.True(t, h.Cancel())
+	assert.Empty(t, controller.Schedules())
+	_, err = collector.ExecEvery(0, "tick", nil)
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+	_, err = controller.Router().ForwardEvery(-time.Second, "Collector", "tick", nil)
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+
+	standalone := NewCollectorService("Standalone", logger)
+	_, err = standalone.ExecAfter(time.Second, "tick", nil)
+	assert.ErrorIs(t, err, ErrNoRouter)
+	_, err = standalone.ExecCron("* * * * *", "tick", nil)
+	assert.ErrorIs(t, err, ErrNoRouter)
+}
+
+func TestServiceRouter_ForwardCron(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	_, err := controller.Router().ForwardCron("invalid", "Collector", "", nil)
+	assert.Error(t, err)
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	controller.scheduler.now = func() time.Time { return now }
+	h, err := controller.Router().ForwardCron("0 12 * * *", "Collector", "noon", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC), h.Next())
+	assert.True(t, controller.Schedules()[0].Recurring)
+	assert.True(t, h.Cancel())
+
+	_, err = controller.Router().ForwardCron("0 0 30 2 *", "Collector", "never", nil)
+	assert.ErrorIs(t, err, ErrInvalidCron, "expression never matching must be rejected")
+	collector := NewCollectorService("Collector", logger)
+	collector.SetRouter(controller)
+	_, err = collector.ExecCron("0 0 30 2 *", "never", nil)
+	assert.ErrorIs(t, err, ErrInvalidCron)
+	assert.Empty(t, controller.Schedules())
+}
+
+func TestServiceController_Unregister_CancelsSchedules(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	collector := NewCollectorService("Collector", logger)
+	collector.SetRouter(controller)
+	controller.Register(collector)
+	other := NewCollectorService("Other", logger)
+	other.SetRouter(controller)
+	controller.Register(other)
+	h1, err := collector.ExecEvery(10*time.Millisecond, "tick", nil)
+	assert.NoError(t, err)
+	h2, err := controller.Router().ForwardEvery(10*time.Millisecond, "Collector", "tick", nil)
+	assert.NoError(t, err)
+	h3, err := other.ExecEvery(time.Hour, "tick", nil)
+	assert.NoError(t, err)
+
+	controller.Unregister("Collector")
+	assert.True(t, h1.Next().IsZero())
+	assert.True(t, h2.Next().IsZero())
+	schedules := controller.Schedules()
+	assert.Len(t, schedules, 1, "schedules of other services must be kept")
+	assert.Equal(t, h3.ID(), schedules[0].ID)
+	assert.True(t, h3.Cancel())
+}
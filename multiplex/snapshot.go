@@ -0,0 +1,183 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// errorRecord is an error with the time it occurred.
+type errorRecord struct {
+	err  error
+	time time.Time
+}
+
+// ServiceSnapshot describes the state of a service at a point in time.
+// Fields other than ServiceID and DesiredWorkers are only available for services embedding ServiceCore.
+//
+// Available since v0.11.0
+type ServiceSnapshot struct {
+	ServiceID string `json:"service_id"`
+	// Whether the service embeds ServiceCore.
+	Core bool `json:"core"`
+	// Number of Process routines the service should use.
+	DesiredWorkers uint64 `json:"desired_workers"`
+	// Number of Process routines currently running.
+	ActiveWorkers uint64 `json:"active_workers"`
+	// Number of requests waiting in the queue.
+	QueueLength int `json:"queue_length"`
+	// Capacity of the queue.
+	QueueCapacity int `json:"queue_capacity"`
+	// Number of requests enqueued but not processed yet.
+	Unprocessed int `json:"unprocessed"`
+	// Whether requests are processed inline.
+	Inline bool `json:"inline"`
+	// Whether the service runs in background mode.
+	Background bool `json:"background"`
+	// Moving average of time spent to process a request.
+	Latency time.Duration `json:"latency"`
+	// State of the CircuitBreaker protecting the service, empty if there is none.
+	Circuit string `json:"circuit,omitempty"`
+	// Message of the last processing error, empty if there is none.
+	LastError string `json:"last_error,omitempty"`
+	// Time of the last processing error.
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+}
+
+// ControllerSnapshot describes the state of a ServiceController and its services at a point in time.
+//
+// Available since v0.11.0
+type ControllerSnapshot struct {
+	Time       time.Time       `json:"time"`
+	Controller ServiceSnapshot `json:"controller"`
+	// Registered services ordered by ID.
+	Services []ServiceSnapshot `json:"services"`
+	// Service receiving messages sent to unknown services.
+	Fallback string `json:"fallback,omitempty"`
+	// Number of requests waiting for their results.
+	PendingRequests int `json:"pending_requests"`
+	// Number of pending schedules.
+	Schedules int `json:"schedules"`
+}
+
+// Return the time the last error reported by processing occurred and the error, or nil if there is none.
+// Errors of the exit request are not recorded.
+//
+// Available since v0.11.0
+func (s ServiceCore) LastError() (time.Time, error) {
+	record, _ := s.i.lastError.Load().(*errorRecord)
+	if record == nil {
+		return time.Time{}, nil
+	}
+	return record.time, record.err
+}
+
+// Return a snapshot of the service.
+//
+// Available since v0.11.0
+func (s ServiceCore) Snapshot() ServiceSnapshot {
+	snapshot := ServiceSnapshot{
+		ServiceID:      s.i.ServiceID,
+		Core:           true,
+		DesiredWorkers: s.WorkerCount(),
+		ActiveWorkers:  s.i.WorkerCounter.Value(),
+		QueueLength:    s.QueueLength(),
+		QueueCapacity:  cap(s.i.MainChan),
+		Unprocessed:    s.Unprocessed(),
+		Inline:         atomic.LoadUint32(&s.i.inline) == 1,
+		Background:     atomic.LoadUint32(&s.i.background) == 1,
+		Latency:        s.Latency(),
+	}
+	if at, err := s.LastError(); err != nil {
+		snapshot.LastError = err.Error()
+		snapshot.LastErrorTime = &at
+	}
+	return snapshot
+}
+
+// Return a snapshot of the controller and all registered services.
+//
+// Available since v0.11.0
+func (s *ServiceController) Snapshot() ControllerSnapshot {
+	s.servicesMu.RLock()
+	services := make([]Service, 0, len(s.services))
+	for _, service := range s.services {
+		services = append(services, service)
+	}
+	fallback := s.fallback
+	s.servicesMu.RUnlock()
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ServiceID() < services[j].ServiceID()
+	})
+
+	snapshot := ControllerSnapshot{
+		Time:            time.Now(),
+		Controller:      s.ServiceCore.Snapshot(),
+		Services:        make([]ServiceSnapshot, 0, len(services)),
+		Fallback:        fallback,
+		PendingRequests: s.Router().PendingRequests(),
+		Schedules:       len(s.Schedules()),
+	}
+	for _, service := range services {
+		var serviceSnapshot ServiceSnapshot
+		if core, ok := service.(interface{ serviceCore() ServiceCore }); ok {
+			serviceSnapshot = core.serviceCore().Snapshot()
+		} else {
+			serviceSnapshot = ServiceSnapshot{
+				ServiceID:      service.ServiceID(),
+				DesiredWorkers: service.WorkerCount(),
+			}
+		}
+		if breaker := s.Router().CircuitBreaker(serviceSnapshot.ServiceID); breaker != nil {
+			serviceSnapshot.Circuit = breaker.State().String()
+		}
+		snapshot.Services = append(snapshot.Services, serviceSnapshot)
+	}
+	return snapshot
+}
+
+// Return a read-only HTTP handler serving the snapshot of the controller as JSON.
+// The snapshot of a single service is served if the service query parameter is set.
+// Only GET and HEAD requests are allowed.
+//
+// Available since v0.11.0
+func NewAdminHandler(controller *ServiceController) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		snapshot := controller.Snapshot()
+		var body interface{} = snapshot
+		if serviceID := r.URL.Query().Get("service"); serviceID != "" {
+			body = nil
+			for _, service := range snapshot.Services {
+				if service.ServiceID == serviceID {
+					body = service
+					break
+				}
+			}
+			if body == nil {
+				http.Error(w, (&NoSuchServiceError{ServiceID: serviceID}).Error(), http.StatusNotFound)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if r.Method == http.MethodHead {
+			return
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(body)
+	})
+}
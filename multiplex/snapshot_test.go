@@ -0,0 +1,131 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+type plainService struct {
+	router *ServiceRouter
+}
+
+func (s *plainService) ServiceID() string                      { return "Plain" }
+func (s *plainService) Router() *ServiceRouter                 { return s.router }
+func (s *plainService) SetWorker(workerCount uint64)           {}
+func (s *plainService) WorkerCount() uint64                    { return 3 }
+func (s *plainService) Exec(command string, params ExecParams) {}
+func (s *plainService) Dispatch(serviceID string, command string, params ExecParams) {
+}
+
+func newSnapshotController(t *testing.T) (*ServiceController, *IdleService) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	svc := NewIdleService(logger)
+	svc.i.CoreProcessHook = func(workerID uint64, msg *ServiceMessage) *HookState {
+		if msg.Command == "fail" {
+			return &HookState{Handled: true, Error: errors.New("failed")}
+		}
+		return &HookState{Handled: msg.Command != "exit"}
+	}
+	svc.SetRouter(controller)
+	svc.SetWorker(2)
+	t.Cleanup(func() {
+		svc.SetWorker(0)
+	})
+	controller.Register(svc)
+	controller.Register(&plainService{router: controller.Router()})
+	controller.Run(false)
+	return controller, svc
+}
+
+func TestServiceController_Snapshot(t *testing.T) {
+	controller, svc := newSnapshotController(t)
+
+	_, err := svc.LastError()
+	assert.NoError(t, err)
+	svc.Exec("fail", nil)
+	assert.True(t, waitCondition(func() bool {
+		_, err := svc.LastError()
+		return err != nil
+	}))
+	controller.Router().SetCircuitBreaker("Idle", NewCircuitBreaker(CircuitBreakerConfig{}))
+
+	snapshot := controller.Snapshot()
+	assert.Equal(t, "Controller", snapshot.Controller.ServiceID)
+	assert.Equal(t, uint64(1), snapshot.Controller.DesiredWorkers)
+	assert.Len(t, snapshot.Services, 2)
+
+	idle := snapshot.Services[0]
+	assert.Equal(t, "Idle", idle.ServiceID)
+	assert.True(t, idle.Core)
+	assert.Equal(t, uint64(2), idle.DesiredWorkers)
+	assert.Equal(t, uint64(2), idle.ActiveWorkers)
+	assert.Equal(t, MainChainCapacity, idle.QueueCapacity)
+	assert.Equal(t, "failed", idle.LastError)
+	assert.NotNil(t, idle.LastErrorTime)
+	assert.Equal(t, "closed", idle.Circuit)
+
+	plain := snapshot.Services[1]
+	assert.Equal(t, "Plain", plain.ServiceID)
+	assert.False(t, plain.Core)
+	assert.Equal(t, uint64(3), plain.DesiredWorkers)
+	assert.Empty(t, plain.Circuit)
+}
+
+func TestServiceCore_Snapshot_Queue(t *testing.T) {
+	svc := NewIdleService(diag.NewDebugLogger(10))
+	svc.Exec("noop", nil)
+	svc.Exec("noop", nil)
+
+	snapshot := svc.Snapshot()
+	assert.Equal(t, uint64(0), snapshot.DesiredWorkers)
+	assert.Equal(t, uint64(0), snapshot.ActiveWorkers)
+	assert.Equal(t, 2, snapshot.QueueLength)
+	assert.Equal(t, 2, snapshot.Unprocessed)
+	assert.Empty(t, snapshot.LastError)
+	assert.Nil(t, snapshot.LastErrorTime)
+}
+
+func TestNewAdminHandler(t *testing.T) {
+	controller, _ := newSnapshotController(t)
+	handler := NewAdminHandler(controller)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var snapshot ControllerSnapshot
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &snapshot))
+	assert.Len(t, snapshot.Services, 2)
+	assert.WithinDuration(t, time.Now(), snapshot.Time, time.Minute)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?service=Idle", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var service ServiceSnapshot
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &service))
+	assert.Equal(t, "Idle", service.ServiceID)
+	assert.Equal(t, uint64(2), service.DesiredWorkers)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?service=Unknown", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	assert.Equal(t, "GET, HEAD", recorder.Header().Get("Allow"))
+}
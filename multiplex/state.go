@@ -33,4 +33,9 @@ const (
 // Available since v0.5.0
 type HookState struct {
 	Handled bool
+
+	// Error occurred while processing the message, if any.
+	//
+	// Available since v0.11.0
+	Error error
 }
@@ -0,0 +1,246 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SupervisorStrategy defines which services are restarted when a Process routine crashes.
+//
+// Available since v0.11.0
+type SupervisorStrategy int8
+
+const (
+	// Only the crashed Process routine is restarted.
+	OneForOne SupervisorStrategy = iota
+
+	// The crashed Process routine is restarted, then all supervised services are restarted.
+	OneForAll
+
+	// The crashed Process routine is restarted, then the crashed service and all supervised services
+	// started after it are restarted.
+	RestForOne
+)
+
+// SupervisorSpec defines how a service is supervised.
+//
+// Available since v0.11.0
+type SupervisorSpec struct {
+	Strategy SupervisorStrategy
+
+	// Maximum number of restarts allowed within Window. Default to 3.
+	MaxRestarts int
+	// Duration used to evaluate restart intensity. Default to 5 seconds.
+	Window time.Duration
+	// Time to wait for Process routines of the service to exit when it is restarted. Default to 5 seconds.
+	ShutdownTimeout time.Duration
+}
+
+// supervisor stores supervision state of a controller.
+type supervisor struct {
+	mu        sync.Mutex
+	specs     map[string]SupervisorSpec
+	restarts  map[string][]time.Time
+	escalate  func(serviceID string, err error)
+	now       func() time.Time
+	restartMu sync.Mutex
+}
+
+// Return new supervisor.
+func newSupervisor() *supervisor {
+	return &supervisor{
+		specs:    make(map[string]SupervisorSpec),
+		restarts: make(map[string][]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Supervise a registered service, crashed Process routines will be restarted according to spec.
+// Restarting a service waits for its Process routines to exit after the requests already queued,
+// calls Stop then Start if it implements LifecycleService, then starts its Process routines again.
+// Panics of Process routines are only recovered for supervised services.
+// The service must embed ServiceCore.
+//
+// Available since v0.11.0
+func (s *ServiceController) Supervise(serviceID string, spec SupervisorSpec) error {
+	service, found := s.Service(serviceID)
+	if !found {
+		return &NoSuchServiceError{ServiceID: serviceID}
+	}
+	core, ok := service.(interface{ serviceCore() ServiceCore })
+	if !ok {
+		return fmt.Errorf("service %q doesn't embed ServiceCore", serviceID)
+	}
+	if spec.MaxRestarts <= 0 {
+		spec.MaxRestarts = 3
+	}
+	if spec.Window <= 0 {
+		spec.Window = 5 * time.Second
+	}
+	if spec.ShutdownTimeout <= 0 {
+		spec.ShutdownTimeout = 5 * time.Second
+	}
+	s.supervisor.mu.Lock()
+	s.supervisor.specs[serviceID] = spec
+	s.supervisor.mu.Unlock()
+	core.serviceCore().i.crashHandler.Store(func(workerID uint64, err error) {
+		s.handleCrash(serviceID, workerID, err)
+	})
+	return nil
+}
+
+// Set the function called when a supervised service exceeds its restart intensity.
+// By default, the controller logs the error and exits.
+//
+// Available since v0.11.0
+func (s *ServiceController) SetEscalationHandler(handler func(serviceID string, err error)) {
+	s.supervisor.mu.Lock()
+	defer s.supervisor.mu.Unlock()
+	s.supervisor.escalate = handler
+}
+
+// Remove supervision state of the service and stop recovering panics of its Process routines.
+func (s *ServiceController) unsupervise(serviceID string, service Service, found bool) {
+	s.supervisor.mu.Lock()
+	delete(s.supervisor.specs, serviceID)
+	delete(s.supervisor.restarts, serviceID)
+	s.supervisor.mu.Unlock()
+	if !found {
+		return
+	}
+	if core, ok := service.(interface{ serviceCore() ServiceCore }); ok {
+		core.serviceCore().i.crashHandler.Store((func(workerID uint64, err error))(nil))
+	}
+}
+
+// Restart crashed Process routine of a supervised service according to its strategy.
+// Crashes of services no longer supervised are only logged.
+func (s *ServiceController) handleCrash(serviceID string, workerID uint64, err error) {
+	sup := s.supervisor
+	sup.mu.Lock()
+	spec, found := sup.specs[serviceID]
+	if !found {
+		sup.mu.Unlock()
+		s.i.Logger.Errorf(err, "Supervisor: Service %s#%d crashed but is no longer supervised.", serviceID, workerID)
+		return
+	}
+	now := sup.now()
+	restarts := []time.Time{}
+	for _, t := range sup.restarts[serviceID] {
+		if now.Sub(t) < spec.Window {
+			restarts = append(restarts, t)
+		}
+	}
+	restarts = append(restarts, now)
+	sup.restarts[serviceID] = restarts
+	escalate := sup.escalate
+	sup.mu.Unlock()
+
+	if len(restarts) > spec.MaxRestarts {
+		intensityErr := &RestartIntensityError{
+			ServiceID: serviceID,
+			Restarts:  len(restarts),
+			Window:    spec.Window,
+			Err:       err,
+		}
+		s.i.Logger.Errorf(intensityErr, "Supervisor: Service %s escalated to controller.", serviceID)
+		if escalate != nil {
+			escalate(serviceID, intensityErr)
+		} else {
+			s.Exec("exit", nil)
+		}
+		return
+	}
+
+	s.i.Logger.Warnf("Supervisor: Restarting %s#%d (%d/%d).", serviceID, workerID, len(restarts), spec.MaxRestarts)
+	s.restartWorkers(serviceID)
+	switch spec.Strategy {
+	case OneForAll:
+		for _, id := range s.supervisedOrder() {
+			s.restartService(id)
+		}
+	case RestForOne:
+		rest := false
+		for _, id := range s.supervisedOrder() {
+			if id == serviceID {
+				rest = true
+			}
+			if rest {
+				s.restartService(id)
+			}
+		}
+	}
+}
+
+// Restart the service by stopping its Process routines, calling its lifecycle hooks then starting them again.
+func (s *ServiceController) restartService(serviceID string) {
+	s.supervisor.restartMu.Lock()
+	defer s.supervisor.restartMu.Unlock()
+	service, found := s.Service(serviceID)
+	if !found {
+		return
+	}
+	s.supervisor.mu.Lock()
+	timeout := s.supervisor.specs[serviceID].ShutdownTimeout
+	s.supervisor.mu.Unlock()
+	var workerCount uint64
+	core, hasCore := service.(interface{ serviceCore() ServiceCore })
+	if hasCore {
+		// Replace crashed Process routines first so each exit request is consumed by a running one.
+		for core.serviceCore().respawn() {
+		}
+		workerCount = core.serviceCore().WorkerCount()
+		core.serviceCore().SetWorker(0)
+		if !core.serviceCore().WaitWorker(timeout) {
+			s.i.Logger.Warnf("Supervisor: Process routines of %s didn't exit in %v.", serviceID, timeout)
+		}
+	}
+	if lifecycle, ok := service.(LifecycleService); ok {
+		if err := lifecycle.Stop(); err != nil {
+			s.i.Logger.Errorf(err, "Supervisor: Service %s failed to stop.", serviceID)
+		}
+		if err := lifecycle.Start(); err != nil {
+			s.i.Logger.Errorf(err, "Supervisor: Service %s failed to start.", serviceID)
+		}
+	}
+	if hasCore {
+		core.serviceCore().SetWorker(workerCount)
+	}
+	s.i.Logger.Infof("Supervisor: Service %s restarted.", serviceID)
+}
+
+// Start missing Process routines of the service.
+func (s *ServiceController) restartWorkers(serviceID string) {
+	service, found := s.Service(serviceID)
+	if !found {
+		return
+	}
+	if core, ok := service.(interface{ serviceCore() ServiceCore }); ok {
+		for core.serviceCore().respawn() {
+		}
+	}
+}
+
+// Return supervised services in startup order.
+func (s *ServiceController) supervisedOrder() []string {
+	order, err := s.StartupOrder()
+	if err != nil {
+		return nil
+	}
+	s.supervisor.mu.Lock()
+	defer s.supervisor.mu.Unlock()
+	supervised := []string{}
+	for _, serviceID := range order {
+		if _, found := s.supervisor.specs[serviceID]; found {
+			supervised = append(supervised, serviceID)
+		}
+	}
+	return supervised
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestServiceController_Supervise_OneForOne(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	svc := NewLifecycleTestService("Api", controller, &lifecycleEvents{})
+	svc.i.CoreProcessHook = crashProcessHook
+	assert.NoError(t, controller.Supervise("Api", SupervisorSpec{}))
+	controller.SetWorker(1)
+	svc.SetWorker(2)
+	assert.True(t, svc.WaitWorker(time.Second))
+
+	_, err := svc.Request("Api", "crash", ExecParams{}, time.Second)
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "crash", panicErr.Value)
+	assert.True(t, waitCondition(func() bool { return svc.i.WorkerCounter.Value() == 2 }))
+	assert.Equal(t, uint64(3), svc.i.WorkerID)
+	assert.Empty(t, svc.events.all(), "OneForOne must not restart the service")
+
+	svc.SetWorker(0)
+	assert.True(t, svc.WaitWorker(time.Second))
+}
+
+func TestServiceController_Supervise_Unsupervised(t *testing.T) {
+	if os.Getenv("MULTIPLEX_UNSUPERVISED_CRASH") == "1" {
+		controller := NewServiceController(diag.NewDebugLogger(10))
+		svc := NewLifecycleTestService("Api", controller, &lifecycleEvents{})
+		svc.i.CoreProcessHook = crashProcessHook
+		svc.SetWorker(1)
+		svc.Exec("crash", ExecParams{})
+		time.Sleep(time.Second)
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestServiceController_Supervise_Unsupervised$")
+	cmd.Env = append(os.Environ(), "MULTIPLEX_UNSUPERVISED_CRASH=1")
+	output, err := cmd.CombinedOutput()
+	assert.Error(t, err, "panic of unsupervised service must not be recovered")
+	assert.Contains(t, string(output), "panic: crash")
+
+	controller := NewServiceController(diag.NewDebugLogger(10))
+	assert.Error(t, controller.Supervise("Unknown", SupervisorSpec{}))
+}
+
+func TestServiceController_Supervise_Unregister(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	svc := NewLifecycleTestService("Api", controller, &lifecycleEvents{})
+	assert.NoError(t, controller.Supervise("Api", SupervisorSpec{}))
+	controller.SetEscalationHandler(func(serviceID string, err error) {
+		t.Errorf("crash of unregistered service %s must not be escalated", serviceID)
+	})
+	controller.SetWorker(1)
+	assert.True(t, controller.WaitWorker(time.Second))
+	handler := svc.i.crashHandlerFunc()
+
+	controller.Unregister("Api")
+	assert.False(t, svc.i.supervised(), "unregistered service must not be supervised")
+	// A Process routine that crashed before Unregister may still report it.
+	handler(1, &PanicError{Value: "crash"})
+	controller.SetEscalationHandler(nil)
+	handler(1, &PanicError{Value: "crash"})
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, uint64(1), controller.i.WorkerCounter.Value(), "controller must stay up")
+	controller.supervisor.mu.Lock()
+	assert.NotContains(t, controller.supervisor.restarts, "Api")
+	controller.supervisor.mu.Unlock()
+
+	controller.SetWorker(0)
+	assert.True(t, controller.WaitWorker(time.Second))
+}
+
+func TestServiceController_Supervise_OneForAll(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	events := &lifecycleEvents{}
+	api := NewLifecycleTestService("Api", controller, events)
+	api.i.CoreProcessHook = crashProcessHook
+	cache := NewLifecycleTestService("Cache", controller, events)
+	NewLifecycleTestService("Database", controller, events)
+	controller.DependsOn("Api", "Cache")
+	controller.DependsOn("Cache", "Database")
+	for _, serviceID := range []string{"Api", "Cache", "Database"} {
+		assert.NoError(t, controller.Supervise(serviceID, SupervisorSpec{Strategy: OneForAll}))
+	}
+	api.SetWorker(1)
+	assert.True(t, api.WaitWorker(time.Second))
+	cache.SetWorker(2)
+	assert.True(t, cache.WaitWorker(time.Second))
+
+	api.Exec("crash", ExecParams{})
+	assert.True(t, waitCondition(func() bool { return len(events.all()) == 6 }))
+	assert.Equal(t, []string{
+		"Database.stop", "Database.start",
+		"Cache.stop", "Cache.start",
+		"Api.stop", "Api.start",
+	}, events.all())
+	assert.True(t, waitCondition(func() bool { return cache.i.WorkerID == 4 }), "healthy siblings must be restarted")
+	assert.True(t, cache.WaitWorker(time.Second))
+	assert.Equal(t, uint64(2), cache.i.WorkerCounter.Value())
+	assert.True(t, api.WaitWorker(time.Second))
+}
+
+func TestServiceController_Supervise_RestForOne(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	events := &lifecycleEvents{}
+	NewLifecycleTestService("Api", controller, events)
+	cache := NewLifecycleTestService("Cache", controller, events)
+	cache.i.CoreProcessHook = crashProcessHook
+	NewLifecycleTestService("Database", controller, events)
+	controller.DependsOn("Api", "Cache")
+	controller.DependsOn("Cache", "Database")
+	for _, serviceID := range []string{"Api", "Cache", "Database"} {
+		assert.NoError(t, controller.Supervise(serviceID, SupervisorSpec{Strategy: RestForOne}))
+	}
+	cache.SetWorker(1)
+	assert.True(t, cache.WaitWorker(time.Second))
+
+	cache.Exec("crash", ExecParams{})
+	assert.True(t, waitCondition(func() bool { return len(events.all()) == 4 }))
+	assert.Equal(t, []string{"Cache.stop", "Cache.start", "Api.stop", "Api.start"}, events.all())
+}
+
+func TestServiceController_Supervise_Escalation(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	svc := NewLifecycleTestService("Api", controller, &lifecycleEvents{})
+	svc.i.CoreProcessHook = crashProcessHook
+	assert.NoError(t, controller.Supervise("Api", SupervisorSpec{MaxRestarts: 2, Window: time.Minute}))
+	escalated := make(chan error, 1)
+	controller.SetEscalationHandler(func(serviceID string, err error) {
+		escalated <- err
+	})
+	svc.SetWorker(1)
+	assert.True(t, svc.WaitWorker(time.Second))
+
+	for i := 0; i < 3; i++ {
+		svc.Exec("crash", ExecParams{})
+	}
+	select {
+	case err := <-escalated:
+		var intensityErr *RestartIntensityError
+		assert.True(t, errors.As(err, &intensityErr))
+		assert.Equal(t, "Api", intensityErr.ServiceID)
+		assert.Equal(t, 3, intensityErr.Restarts)
+		var panicErr *PanicError
+		assert.True(t, errors.As(err, &panicErr))
+	case <-time.After(time.Second):
+		t.Fatal("restart intensity must be escalated")
+	}
+	assert.Equal(t, uint64(0), svc.i.WorkerCounter.Value())
+}
+
+func crashProcessHook(workerID uint64, msg *ServiceMessage) *HookState {
+	if msg.Command == "crash" {
+		panic("crash")
+	}
+	return &HookState{Handled: false}
+}
+
+func waitCondition(condition func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return condition()
+}
@@ -0,0 +1,267 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"math"
+	"sync/atomic"
+)
+
+// ErrOverflow is returned by checked arithmetic when the result can't be represented.
+//
+// Available since v0.11.0
+var ErrOverflow = errors.New("arithmetic overflow")
+
+// ErrDivideByZero is returned by checked arithmetic when dividing by zero.
+//
+// Available since v0.11.0
+var ErrDivideByZero = errors.New("division by zero")
+
+// Number is the set of integer and floating-point types supported by ThreadSafe.
+//
+// Available since v0.11.0
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Struct ThreadSafe is a lock-free thread-safe number. The zero value is ready to use.
+// Operations are implemented with atomic compare-and-swap, floating-point values are compared by their bits.
+// Add, Sub and Mul wrap around on overflow like Go arithmetic, Checked variants report ErrOverflow instead.
+//
+// Available since v0.11.0
+type ThreadSafe[T Number] struct {
+	// bits must go first to guarantee alignment for atomic operations.
+	bits uint64
+}
+
+// Return current value.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) Value() T {
+	return fromBits[T](atomic.LoadUint64(&c.bits))
+}
+
+// Set value.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) Set(value T) {
+	atomic.StoreUint64(&c.bits, toBits(value))
+}
+
+// Set value and return the previous value.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) Swap(value T) T {
+	return fromBits[T](atomic.SwapUint64(&c.bits, toBits(value)))
+}
+
+// Set value to new if current value is old. Return whether the value was set.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) CompareAndSwap(old, new T) bool {
+	return atomic.CompareAndSwapUint64(&c.bits, toBits(old), toBits(new))
+}
+
+// Add n to current value and return the new value.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) Add(n T) T {
+	value, _ := c.update(func(value T) (T, error) {
+		return value + n, nil
+	})
+	return value
+}
+
+// Subtract n from current value and return the new value.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) Sub(n T) T {
+	value, _ := c.update(func(value T) (T, error) {
+		return value - n, nil
+	})
+	return value
+}
+
+// Multiply current value with n and return the new value.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) Mul(n T) T {
+	value, _ := c.update(func(value T) (T, error) {
+		return value * n, nil
+	})
+	return value
+}
+
+// Divide current value by n and return the new value.
+// ErrDivideByZero is returned and the value is unchanged if n is zero.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) Div(n T) (T, error) {
+	return c.update(func(value T) (T, error) {
+		if n == 0 {
+			return value, ErrDivideByZero
+		}
+		return value / n, nil
+	})
+}
+
+// Add n to current value and return the new value.
+// ErrOverflow is returned and the value is unchanged if the result overflows.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) CheckedAdd(n T) (T, error) {
+	return c.update(func(value T) (T, error) {
+		result := value + n
+		if isFloat[T]() {
+			return result, checkFloat(result, value, n)
+		}
+		if (n > 0 && result < value) || (n < 0 && result > value) {
+			return value, ErrOverflow
+		}
+		return result, nil
+	})
+}
+
+// Subtract n from current value and return the new value.
+// ErrOverflow is returned and the value is unchanged if the result overflows.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) CheckedSub(n T) (T, error) {
+	return c.update(func(value T) (T, error) {
+		result := value - n
+		if isFloat[T]() {
+			return result, checkFloat(result, value, n)
+		}
+		if (n > 0 && result > value) || (n < 0 && result < value) {
+			return value, ErrOverflow
+		}
+		return result, nil
+	})
+}
+
+// Multiply current value with n and return the new value.
+// ErrOverflow is returned and the value is unchanged if the result overflows.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) CheckedMul(n T) (T, error) {
+	return c.update(func(value T) (T, error) {
+		result := value * n
+		if isFloat[T]() {
+			return result, checkFloat(result, value, n)
+		}
+		if value != 0 && result/value != n {
+			return value, ErrOverflow
+		}
+		// The only remaining overflow is the most negative value multiplied by -1.
+		var minusOne T = 0
+		minusOne--
+		if minusOne < 0 && ((value == minusOne && result == n && n < 0) || (n == minusOne && result == value && value < 0)) {
+			return value, ErrOverflow
+		}
+		return result, nil
+	})
+}
+
+// Divide current value by n and return the new value.
+// ErrDivideByZero is returned if n is zero and ErrOverflow is returned if the result overflows,
+// the value is unchanged in both cases.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) CheckedDiv(n T) (T, error) {
+	return c.update(func(value T) (T, error) {
+		if n == 0 {
+			return value, ErrDivideByZero
+		}
+		result := value / n
+		if isFloat[T]() {
+			return result, checkFloat(result, value, n)
+		}
+		var minusOne T = 0
+		minusOne--
+		if minusOne < 0 && n == minusOne && value < 0 && result == value {
+			return value, ErrOverflow
+		}
+		return result, nil
+	})
+}
+
+// Set value to v if v is smaller than current value. Return whether the value was set.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) UpdateMin(v T) bool {
+	for {
+		bits := atomic.LoadUint64(&c.bits)
+		if !(v < fromBits[T](bits)) {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(&c.bits, bits, toBits(v)) {
+			return true
+		}
+	}
+}
+
+// Set value to v if v is larger than current value. Return whether the value was set.
+//
+// Available since v0.11.0
+func (c *ThreadSafe[T]) UpdateMax(v T) bool {
+	for {
+		bits := atomic.LoadUint64(&c.bits)
+		if !(v > fromBits[T](bits)) {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(&c.bits, bits, toBits(v)) {
+			return true
+		}
+	}
+}
+
+// Apply fn to current value atomically. The value is unchanged if fn returns an error.
+func (c *ThreadSafe[T]) update(fn func(value T) (T, error)) (T, error) {
+	for {
+		bits := atomic.LoadUint64(&c.bits)
+		result, err := fn(fromBits[T](bits))
+		if err != nil {
+			return fromBits[T](bits), err
+		}
+		if atomic.CompareAndSwapUint64(&c.bits, bits, toBits(result)) {
+			return result, nil
+		}
+	}
+}
+
+// Return whether T is a floating-point type.
+func isFloat[T Number]() bool {
+	var one T = 1
+	return one/2 != 0
+}
+
+// Return ErrOverflow if result is infinite while operands are finite.
+func checkFloat[T Number](result, x, y T) error {
+	if math.IsInf(float64(result), 0) && !math.IsInf(float64(x), 0) && !math.IsInf(float64(y), 0) {
+		return ErrOverflow
+	}
+	return nil
+}
+
+// Return bits representing the value.
+func toBits[T Number](value T) uint64 {
+	if isFloat[T]() {
+		return math.Float64bits(float64(value))
+	}
+	return uint64(value)
+}
+
+// Return value represented by bits.
+func fromBits[T Number](bits uint64) T {
+	if isFloat[T]() {
+		return T(math.Float64frombits(bits))
+	}
+	return T(bits)
+}
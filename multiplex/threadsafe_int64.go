@@ -10,6 +10,8 @@ import "sync"
 
 // Struct Int64ThreadSafe is a thread-safe counter with underlying value of int64.
 //
+// Deprecated: Use ThreadSafe[int64] instead, which is lock-free.
+//
 // Available since v0.5.0
 type Int64ThreadSafe struct {
 	value int64
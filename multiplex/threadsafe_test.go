@@ -0,0 +1,242 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThreadSafe_SetAndValue(t *testing.T) {
+	var counter ThreadSafe[int64]
+	assert.Equal(t, int64(0), counter.Value())
+
+	counter.Set(-10)
+	assert.Equal(t, int64(-10), counter.Value())
+
+	var ratio ThreadSafe[float64]
+	ratio.Set(0.25)
+	assert.Equal(t, 0.25, ratio.Value())
+
+	var small ThreadSafe[float32]
+	small.Set(-1.5)
+	assert.Equal(t, float32(-1.5), small.Value())
+}
+
+func TestThreadSafe_Arithmetic(t *testing.T) {
+	var counter ThreadSafe[int32]
+	assert.Equal(t, int32(5), counter.Add(5))
+	assert.Equal(t, int32(-3), counter.Sub(8))
+	assert.Equal(t, int32(-12), counter.Mul(4))
+
+	value, err := counter.Div(5)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(-2), value)
+
+	value, err = counter.Div(0)
+	assert.ErrorIs(t, err, ErrDivideByZero)
+	assert.Equal(t, int32(-2), value)
+	assert.Equal(t, int32(-2), counter.Value())
+
+	var ratio ThreadSafe[float64]
+	ratio.Set(1)
+	assert.Equal(t, 0.5, ratio.Mul(0.5))
+	fraction, err := ratio.Div(4)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.125, fraction)
+}
+
+func TestThreadSafe_SwapAndCompareAndSwap(t *testing.T) {
+	var counter ThreadSafe[uint16]
+	counter.Set(7)
+
+	assert.Equal(t, uint16(7), counter.Swap(9))
+	assert.False(t, counter.CompareAndSwap(7, 11))
+	assert.Equal(t, uint16(9), counter.Value())
+	assert.True(t, counter.CompareAndSwap(9, 11))
+	assert.Equal(t, uint16(11), counter.Value())
+}
+
+func TestThreadSafe_CheckedAdd(t *testing.T) {
+	var counter ThreadSafe[int8]
+	counter.Set(120)
+
+	value, err := counter.CheckedAdd(7)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(127), value)
+
+	value, err = counter.CheckedAdd(1)
+	assert.ErrorIs(t, err, ErrOverflow)
+	assert.Equal(t, int8(127), value)
+
+	counter.Set(math.MinInt8)
+	_, err = counter.CheckedAdd(-1)
+	assert.ErrorIs(t, err, ErrOverflow)
+	assert.Equal(t, int8(math.MinInt8), counter.Value())
+
+	// Unchecked Add wraps around.
+	assert.Equal(t, int8(math.MaxInt8), counter.Add(-1))
+}
+
+func TestThreadSafe_CheckedSub(t *testing.T) {
+	var counter ThreadSafe[uint8]
+	counter.Set(3)
+
+	value, err := counter.CheckedSub(3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(0), value)
+
+	_, err = counter.CheckedSub(1)
+	assert.ErrorIs(t, err, ErrOverflow)
+	assert.Equal(t, uint8(0), counter.Value())
+
+	var signed ThreadSafe[int8]
+	signed.Set(100)
+	_, err = signed.CheckedSub(-28)
+	assert.ErrorIs(t, err, ErrOverflow)
+	value8, err := signed.CheckedSub(-27)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(127), value8)
+}
+
+func TestThreadSafe_CheckedMul(t *testing.T) {
+	var counter ThreadSafe[uint8]
+	counter.Set(16)
+
+	value, err := counter.CheckedMul(15)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(240), value)
+
+	_, err = counter.CheckedMul(2)
+	assert.ErrorIs(t, err, ErrOverflow)
+	assert.Equal(t, uint8(240), counter.Value())
+
+	var signed ThreadSafe[int8]
+	signed.Set(math.MinInt8)
+	_, err = signed.CheckedMul(-1)
+	assert.ErrorIs(t, err, ErrOverflow)
+	signed.Set(-1)
+	_, err = signed.CheckedMul(math.MinInt8)
+	assert.ErrorIs(t, err, ErrOverflow)
+	signed.Set(-64)
+	value8, err := signed.CheckedMul(2)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(math.MinInt8), value8)
+}
+
+func TestThreadSafe_CheckedDiv(t *testing.T) {
+	var counter ThreadSafe[int64]
+	counter.Set(math.MinInt64)
+
+	_, err := counter.CheckedDiv(0)
+	assert.ErrorIs(t, err, ErrDivideByZero)
+	_, err = counter.CheckedDiv(-1)
+	assert.ErrorIs(t, err, ErrOverflow)
+	assert.Equal(t, int64(math.MinInt64), counter.Value())
+
+	value, err := counter.CheckedDiv(math.MinInt64)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), value)
+}
+
+func TestThreadSafe_CheckedFloat(t *testing.T) {
+	var ratio ThreadSafe[float64]
+	ratio.Set(math.MaxFloat64)
+
+	_, err := ratio.CheckedMul(2)
+	assert.ErrorIs(t, err, ErrOverflow)
+	assert.Equal(t, math.MaxFloat64, ratio.Value())
+
+	_, err = ratio.CheckedDiv(0)
+	assert.ErrorIs(t, err, ErrDivideByZero)
+
+	ratio.Set(math.Inf(1))
+	value, err := ratio.CheckedAdd(1)
+	assert.NoError(t, err)
+	assert.True(t, math.IsInf(value, 1))
+}
+
+func TestThreadSafe_UpdateMinMax(t *testing.T) {
+	var low, high ThreadSafe[int64]
+	low.Set(math.MaxInt64)
+	high.Set(math.MinInt64)
+
+	var wg sync.WaitGroup
+	for i := int64(-500); i <= 500; i++ {
+		wg.Add(1)
+		go func(v int64) {
+			defer wg.Done()
+			low.UpdateMin(v)
+			high.UpdateMax(v)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(-500), low.Value())
+	assert.Equal(t, int64(500), high.Value())
+	assert.False(t, low.UpdateMin(-500))
+	assert.True(t, high.UpdateMax(501))
+}
+
+func TestThreadSafe_Concurrency(t *testing.T) {
+	var counter ThreadSafe[uint64]
+	var ratio ThreadSafe[float64]
+
+	var wg sync.WaitGroup
+	for i := 0; i < 1000; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			counter.Add(2)
+			counter.Sub(1)
+			ratio.Add(0.5)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint64(1000), counter.Value())
+	assert.Equal(t, 500.0, ratio.Value())
+}
+
+func BenchmarkThreadSafe_Add(b *testing.B) {
+	var counter ThreadSafe[uint64]
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			counter.Add(1)
+		}
+	})
+}
+
+func BenchmarkUint64ThreadSafe_Add(b *testing.B) {
+	counter := &Uint64ThreadSafe{}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			counter.Add(1)
+		}
+	})
+}
+
+func BenchmarkThreadSafe_Value(b *testing.B) {
+	var counter ThreadSafe[int64]
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			counter.Value()
+		}
+	})
+}
+
+func BenchmarkInt64ThreadSafe_Value(b *testing.B) {
+	counter := &Int64ThreadSafe{}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			counter.Value()
+		}
+	})
+}
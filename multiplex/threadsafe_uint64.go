@@ -10,6 +10,8 @@ import "sync"
 
 // Struct Uint64ThreadSafe is a thread-safe counter with underlying value of uint64.
 //
+// Deprecated: Use ThreadSafe[uint64] instead, which is lock-free.
+//
 // Available since v0.5.0
 type Uint64ThreadSafe struct {
 	value uint64
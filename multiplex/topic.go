@@ -0,0 +1,302 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Parameter key holding the topic a message was published to.
+//
+// Available since v0.11.0
+const TopicKey = "topic"
+
+// ErrInvalidTopic is returned when a topic or a topic pattern is malformed.
+//
+// Available since v0.11.0
+var ErrInvalidTopic = errors.New("invalid topic")
+
+// ErrSubscriberFull is matched by errors.Is for all PublishError.
+//
+// Available since v0.11.0
+var ErrSubscriberFull = errors.New("subscriber full")
+
+// PublishError is returned when a published message was dropped for some subscribers
+// because their delivery queues were full.
+//
+// Available since v0.11.0
+type PublishError struct {
+	Topic   string
+	Dropped []string
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("message on topic %s dropped for full subscribers %s", e.Topic, strings.Join(e.Dropped, ", "))
+}
+
+// Report whether target is ErrSubscriberFull.
+//
+// Available since v0.11.0
+func (e *PublishError) Is(target error) bool {
+	return target == ErrSubscriberFull
+}
+
+// topicRegistry stores subscriptions of services to topics.
+type topicRegistry struct {
+	mu          sync.RWMutex
+	subscribers map[string]*subscriber
+}
+
+// subscriber owns the delivery queue of a service so a slow service doesn't block others.
+// Messages are executed on the service directly, bypassing the forwarding chain of the router.
+type subscriber struct {
+	service  Service
+	patterns map[string]bool
+	queue    chan *ServiceMessage
+	done     chan struct{}
+}
+
+// Return new empty topicRegistry.
+func newTopicRegistry() *topicRegistry {
+	return &topicRegistry{
+		subscribers: make(map[string]*subscriber),
+	}
+}
+
+// Subscribe a registered service to topics matching the pattern.
+// Topic is a dot-separated list of segments. In pattern, "*" matches exactly one segment
+// and "#" matches zero or more segments.
+//
+// Available since v0.11.0
+func (s *ServiceController) Subscribe(serviceID, pattern string) error {
+	if !isValidTopic(pattern, true) {
+		return fmt.Errorf("%w: %q", ErrInvalidTopic, pattern)
+	}
+	s.topics.mu.Lock()
+	defer s.topics.mu.Unlock()
+	// Unregister removes subscriptions after the service, so the lookup must be done with the lock held.
+	service, found := s.Service(serviceID)
+	if !found {
+		return &NoSuchServiceError{ServiceID: serviceID}
+	}
+	sub, found := s.topics.subscribers[serviceID]
+	if !found {
+		sub = &subscriber{
+			service:  service,
+			patterns: make(map[string]bool),
+			queue:    make(chan *ServiceMessage, MainChainCapacity),
+			done:     make(chan struct{}),
+		}
+		s.topics.subscribers[serviceID] = sub
+		go sub.deliver()
+	}
+	sub.patterns[pattern] = true
+	return nil
+}
+
+// Unsubscribe a service from a pattern previously subscribed.
+// Messages already queued for the service are still delivered.
+//
+// Available since v0.11.0
+func (s *ServiceController) Unsubscribe(serviceID, pattern string) {
+	s.topics.mu.Lock()
+	defer s.topics.mu.Unlock()
+	sub, found := s.topics.subscribers[serviceID]
+	if !found {
+		return
+	}
+	delete(sub.patterns, pattern)
+	if len(sub.patterns) == 0 {
+		delete(s.topics.subscribers, serviceID)
+		close(sub.done)
+	}
+}
+
+// Unsubscribe a service from all patterns.
+// Messages already queued for the service are still delivered.
+//
+// Available since v0.11.0
+func (s *ServiceController) UnsubscribeAll(serviceID string) {
+	s.topics.mu.Lock()
+	defer s.topics.mu.Unlock()
+	sub, found := s.topics.subscribers[serviceID]
+	if !found {
+		return
+	}
+	delete(s.topics.subscribers, serviceID)
+	close(sub.done)
+}
+
+// Return all patterns the service subscribed to in lexical order.
+//
+// Available since v0.11.0
+func (s *ServiceController) Subscriptions(serviceID string) []string {
+	s.topics.mu.RLock()
+	defer s.topics.mu.RUnlock()
+	patterns := []string{}
+	if sub, found := s.topics.subscribers[serviceID]; found {
+		for pattern := range sub.patterns {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// Publish the message to all services subscribed to the topic.
+// Each subscriber receives its own copy of params with the topic under TopicKey.
+// Publishing doesn't support returns, the "return" parameter is not delivered.
+// Return number of services the message was queued for, and a PublishError listing
+// services the message was dropped for because their queues were full.
+// Deliveries are recorded in the Journal of the controller if any.
+// Unlike Forward, queued messages are executed directly on subscribers without passing through
+// the controller, so a slow subscriber doesn't block others. As a consequence, forwarding interceptors,
+// CircuitBreaker and the fallback service don't apply to published messages.
+//
+// Available since v0.11.0
+func (s *ServiceRouter) Publish(topic, command string, params ExecParams) (int, error) {
+	return s.publish("", topic, command, params)
+}
+
+// Publish the message sent by source service to all services subscribed to the topic.
+func (s *ServiceRouter) publish(source, topic, command string, params ExecParams) (int, error) {
+	if !isValidTopic(topic, false) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidTopic, topic)
+	}
+	topics := s.c.topics
+	topics.mu.RLock()
+	defer topics.mu.RUnlock()
+	count := 0
+	dropped := []string{}
+	for serviceID, sub := range topics.subscribers {
+		if !sub.matches(topic) {
+			continue
+		}
+		msgParams := params.Clone()
+		msgParams.Delete("return")
+		msgParams[TopicKey] = topic
+		msg := &ServiceMessage{
+			Command: command,
+			Params:  msgParams,
+			source:  source,
+		}
+		err := s.journaled(serviceID, msg, func() error {
+			select {
+			case sub.queue <- msg:
+				return nil
+			default:
+				return fmt.Errorf("%w: %s", ErrSubscriberFull, serviceID)
+			}
+		})
+		if err != nil {
+			s.c.i.Logger.Warnf("Subscriber %s is full, message on topic %s dropped.", serviceID, topic)
+			dropped = append(dropped, serviceID)
+			continue
+		}
+		count++
+	}
+	if len(dropped) > 0 {
+		sort.Strings(dropped)
+		return count, &PublishError{Topic: topic, Dropped: dropped}
+	}
+	return count, nil
+}
+
+// Subscribe this service to topics matching the pattern via configurated Router.
+//
+// Available since v0.11.0
+func (s ServiceCore) Subscribe(pattern string) error {
+	return s.i.Router.c.Subscribe(s.i.ServiceID, pattern)
+}
+
+// Publish the message to all services subscribed to the topic via configurated Router.
+// Return values are the same as ServiceRouter.Publish.
+//
+// Available since v0.11.0
+func (s ServiceCore) Publish(topic, command string, params ExecParams) (int, error) {
+	return s.i.Router.publish(s.i.ServiceID, topic, command, params)
+}
+
+// Delivery routine of the subscriber. Once done, remaining queued messages are delivered
+// before exiting. No message is queued after done is closed.
+func (s *subscriber) deliver() {
+	for {
+		select {
+		case msg := <-s.queue:
+			s.service.Exec(msg.Command, msg.Params)
+		case <-s.done:
+			for {
+				select {
+				case msg := <-s.queue:
+					s.service.Exec(msg.Command, msg.Params)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// Return whether the topic matches any patterns of the subscriber.
+func (s *subscriber) matches(topic string) bool {
+	segments := strings.Split(topic, ".")
+	for pattern := range s.patterns {
+		if matchTopic(strings.Split(pattern, "."), segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return whether topic segments match pattern segments.
+func matchTopic(pattern, topic []string) bool {
+	if len(pattern) == 0 {
+		return len(topic) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(topic); i++ {
+			if matchTopic(pattern[1:], topic[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(topic) > 0 && matchTopic(pattern[1:], topic[1:])
+	default:
+		return len(topic) > 0 && pattern[0] == topic[0] && matchTopic(pattern[1:], topic[1:])
+	}
+}
+
+// Return whether the topic is well-formed. Wildcards are only allowed in patterns.
+func isValidTopic(topic string, wildcard bool) bool {
+	if topic == "" {
+		return false
+	}
+	for _, segment := range strings.Split(topic, ".") {
+		if segment == "" {
+			return false
+		}
+		if segment == "*" || segment == "#" {
+			if !wildcard {
+				return false
+			}
+			continue
+		}
+		if strings.ContainsAny(segment, "*#") {
+			return false
+		}
+	}
+	return true
+}
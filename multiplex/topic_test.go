@@ -0,0 +1,189 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		topic    string
+		expected bool
+	}{
+		{"orders.created", "orders.created", true},
+		{"orders.created", "orders.updated", false},
+		{"orders.*", "orders.created", true},
+		{"orders.*", "orders.created.eu", false},
+		{"*.created", "orders.created", true},
+		{"orders.#", "orders", true},
+		{"orders.#", "orders.created.eu", true},
+		{"#", "orders.created", true},
+		{"#.eu", "orders.created.eu", true},
+		{"#.eu", "orders.created.us", false},
+		{"orders.#.eu", "orders.eu", true},
+		{"orders.*.eu", "orders.eu", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.topic, func(t *testing.T) {
+			result := matchTopic(strings.Split(tt.pattern, "."), strings.Split(tt.topic, "."))
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestIsValidTopic(t *testing.T) {
+	assert.True(t, isValidTopic("orders.created", false))
+	assert.False(t, isValidTopic("orders.*", false), "wildcard must not be allowed in topic")
+	assert.True(t, isValidTopic("orders.*", true))
+	assert.False(t, isValidTopic("", true))
+	assert.False(t, isValidTopic("orders..created", true))
+	assert.False(t, isValidTopic("orders.cre*", true))
+}
+
+func TestServiceController_Subscribe(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	collector := NewCollectorService("Collector", logger)
+	collector.SetRouter(controller)
+	err := controller.Subscribe("Collector", "orders.*")
+	assert.Error(t, err, "unregistered service must not be subscribed")
+	controller.Register(collector)
+	err = controller.Subscribe("Collector", "orders..*")
+	assert.True(t, errors.Is(err, ErrInvalidTopic))
+	assert.NoError(t, collector.Subscribe("orders.*"))
+	assert.NoError(t, collector.Subscribe("billing.#"))
+	assert.Equal(t, []string{"billing.#", "orders.*"}, controller.Subscriptions("Collector"))
+	controller.Unsubscribe("Collector", "orders.*")
+	assert.Equal(t, []string{"billing.#"}, controller.Subscriptions("Collector"))
+	controller.Unregister("Collector")
+	assert.Empty(t, controller.Subscriptions("Collector"), "subscriptions must be removed on unregister")
+}
+
+func TestServiceRouter_Publish(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	orders := NewCollectorService("Orders", logger)
+	orders.SetRouter(controller)
+	orders.SetWorker(1)
+	controller.Register(orders)
+	audit := NewCollectorService("Audit", logger)
+	audit.SetRouter(controller)
+	audit.SetWorker(1)
+	controller.Register(audit)
+	orders.Subscribe("orders.*")
+	audit.Subscribe("#")
+	audit.Subscribe("orders.created")
+
+	params := ExecParams{"id": 1}
+	params.ExpectReturn()
+	count, err := controller.Router().Publish("orders.created", "notify", params)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count, "message must be delivered once per service")
+	for _, svc := range []*CollectorService{orders, audit} {
+		msg := svc.Receive(t)
+		assert.Equal(t, "notify", msg.Command)
+		assert.Equal(t, "orders.created", msg.GetParam(TopicKey, ""))
+		assert.Equal(t, 1, msg.GetParam("id", 0))
+		assert.Nil(t, msg.Params["return"], "return must not be published")
+	}
+	count, _ = controller.Router().Publish("billing.paid", "notify", nil)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "billing.paid", audit.Receive(t).GetParam(TopicKey, ""))
+	_, err = controller.Router().Publish("orders.*", "notify", nil)
+	assert.True(t, errors.Is(err, ErrInvalidTopic), "wildcard must not be published")
+}
+
+func TestServiceRouter_Publish_SlowSubscriber(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	slow := NewCollectorService("Slow", logger)
+	slow.SetRouter(controller)
+	controller.Register(slow)
+	fast := NewCollectorService("Fast", logger)
+	fast.SetRouter(controller)
+	fast.SetWorker(1)
+	controller.Register(fast)
+	slow.Subscribe("events")
+	fast.Subscribe("events")
+	total := 2*MainChainCapacity + 10
+	var lastErr error
+	for i := 0; i < total; i++ {
+		count, err := controller.Router().Publish("events", "", ExecParams{"seq": i})
+		if err != nil {
+			lastErr = err
+		}
+		assert.GreaterOrEqual(t, count, 1)
+		assert.Equal(t, i, fast.Receive(t).GetParam("seq", -1), "fast subscriber must not be blocked")
+	}
+	assert.Contains(t, logger.LastMessage(), "Subscriber Slow is full", "overflow must be reported")
+	assert.True(t, errors.Is(lastErr, ErrSubscriberFull), "overflow must be returned")
+	var publishErr *PublishError
+	assert.True(t, errors.As(lastErr, &publishErr))
+	assert.Equal(t, &PublishError{Topic: "events", Dropped: []string{"Slow"}}, publishErr)
+}
+
+func TestServiceController_Unsubscribe_DrainsQueue(t *testing.T) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	collector := NewCollectorService("Collector", logger)
+	collector.SetRouter(controller)
+	controller.Register(collector)
+	collector.Subscribe("events")
+	total := MainChainCapacity
+	for i := 0; i < total; i++ {
+		count, err := controller.Router().Publish("events", "", ExecParams{"seq": i})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	}
+	controller.UnsubscribeAll("Collector")
+	collector.SetWorker(1)
+	defer collector.SetWorker(0)
+	for i := 0; i < total; i++ {
+		assert.Equal(t, i, collector.Receive(t).GetParam("seq", -1), "queued messages must be delivered after unsubscribe")
+	}
+}
+
+type CollectorService struct {
+	ServiceCore
+	i        *ServiceCoreInternal
+	received chan *ServiceMessage
+}
+
+func NewCollectorService(serviceID string, logger diag.Logger) *CollectorService {
+	svc := &CollectorService{
+		received: make(chan *ServiceMessage, 4*MainChainCapacity),
+	}
+	svc.i = svc.InitServiceCore(serviceID, logger, svc.coreProcessHook)
+	return svc
+}
+
+func (s *CollectorService) coreProcessHook(workerID uint64, msg *ServiceMessage) *HookState {
+	if msg.Command == "exit" {
+		return &HookState{Handled: false}
+	}
+	s.received <- msg
+	return &HookState{Handled: true}
+}
+
+func (s *CollectorService) Receive(t *testing.T) *ServiceMessage {
+	t.Helper()
+	select {
+	case msg := <-s.received:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatalf("%s: message not received", s.ServiceID())
+		return nil
+	}
+}
@@ -0,0 +1,689 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+const (
+	// Maximum size of an encoded frame exchanged by transports.
+	MaxFrameSize = 16 << 20
+)
+
+const (
+	frameMessage uint8 = iota + 1
+	frameReply
+)
+
+// ErrDisconnected is returned when a TransportClient is not connected to its server.
+//
+// Available since v0.11.0
+var ErrDisconnected = errors.New("transport disconnected")
+
+// ErrReservedCommand is returned when a peer sends a command reserved for internal use, such as "exit".
+//
+// Available since v0.11.0
+var ErrReservedCommand = errors.New("reserved command")
+
+// Commands that can't be received from other processes.
+var reservedCommands = map[string]bool{
+	"exit":       true,
+	ReplyCommand: true,
+}
+
+// RemoteError is an error returned by a service in another process.
+// Only the message of the original error is preserved.
+//
+// Available since v0.11.0
+type RemoteError struct {
+	ServiceID string
+	Command   string
+	Message   string
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("remote service %q failed command %q: %s", e.ServiceID, e.Command, e.Message)
+}
+
+// transportFrame is the unit of data exchanged by transports.
+type transportFrame struct {
+	Kind      uint8
+	ID        uint64
+	ServiceID string
+	Command   string
+	Params    ExecParams
+	Timeout   time.Duration
+	Result    interface{}
+	Error     string
+}
+
+// TransportServerConfig defines how a TransportServer handles requests of its peers.
+//
+// Available since v0.11.0
+type TransportServerConfig struct {
+	// Codec used to encode frames, must match the clients. Default to JSONCodec.
+	Codec Codec
+	// Maximum time to wait for results of requests. Longer or non-positive timeouts
+	// requested by peers are replaced by it. Default to 30 seconds.
+	MaxTimeout time.Duration
+	// Maximum number of requests of a connection waiting for their results at the same time.
+	// Further frames of the connection aren't read until one of them completes. Default to 64.
+	MaxInFlight int
+}
+
+// TransportServer exposes services of a ServiceController to other processes
+// over stream listeners such as Unix domain sockets or TCP.
+//
+// Available since v0.11.0
+type TransportServer struct {
+	controller *ServiceController
+	config     TransportServerConfig
+	exposed    map[string]bool
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+}
+
+// Return new TransportServer exposing specified services of the controller.
+// Services must be exposed explicitly, the server rejects all messages if none is specified.
+// Reserved commands such as "exit" are never accepted.
+//
+// Available since v0.11.0
+func NewTransportServer(controller *ServiceController, config TransportServerConfig, serviceIDs ...string) *TransportServer {
+	if config.Codec == nil {
+		config.Codec = JSONCodec{}
+	}
+	if config.MaxTimeout <= 0 {
+		config.MaxTimeout = 30 * time.Second
+	}
+	if config.MaxInFlight <= 0 {
+		config.MaxInFlight = 64
+	}
+	exposed := make(map[string]bool, len(serviceIDs))
+	for _, serviceID := range serviceIDs {
+		exposed[serviceID] = true
+	}
+	return &TransportServer{
+		controller: controller,
+		config:     config,
+		exposed:    exposed,
+		listeners:  make(map[net.Listener]struct{}),
+		conns:      make(map[net.Conn]struct{}),
+	}
+}
+
+// Listen on the network address, such as "unix" or "tcp", and serve connections in a separate routine.
+// Return the listener for access to its address.
+//
+// Available since v0.11.0
+func (t *TransportServer) Listen(network, address string) (net.Listener, error) {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	go t.Serve(listener)
+	return listener, nil
+}
+
+// Accept connections on the listener until it is closed.
+// Messages received are forwarded via the controller's Router.
+//
+// Available since v0.11.0
+func (t *TransportServer) Serve(listener net.Listener) error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		listener.Close()
+		return ErrDisconnected
+	}
+	t.listeners[listener] = struct{}{}
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.listeners, listener)
+		t.mu.Unlock()
+	}()
+
+	logger := t.controller.i.Logger
+	logger.Infof("Transport: Serving on %s.", listener.Addr())
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if t.isClosed() {
+				return nil
+			}
+			logger.Errorf(err, "Transport: Failed to accept connection on %s.", listener.Addr())
+			return err
+		}
+		go t.serveConn(conn)
+	}
+}
+
+// Close all listeners and connections.
+//
+// Available since v0.11.0
+func (t *TransportServer) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for listener := range t.listeners {
+		listener.Close()
+	}
+	for conn := range t.conns {
+		conn.Close()
+	}
+	return nil
+}
+
+// Return whether the server is closed.
+func (t *TransportServer) isClosed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// Handle frames received from a connection until it is closed.
+func (t *TransportServer) serveConn(conn net.Conn) {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		conn.Close()
+		return
+	}
+	t.conns[conn] = struct{}{}
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.conns, conn)
+		t.mu.Unlock()
+		conn.Close()
+	}()
+
+	logger := t.controller.i.Logger
+	router := t.controller.Router()
+	var writeMu sync.Mutex
+	reply := func(frame *transportFrame) {
+		data, err := encodeFrame(t.config.Codec, frame)
+		if err != nil {
+			logger.Errorf(err, "Transport: Result of command %q from %s can't be encoded.", frame.Command, frame.ServiceID)
+			frame.Result = nil
+			frame.Error = err.Error()
+			if data, err = encodeFrame(t.config.Codec, frame); err != nil {
+				return
+			}
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if _, err := conn.Write(data); err != nil {
+			logger.Errorf(err, "Transport: Reply to %s can't be sent.", conn.RemoteAddr())
+		}
+	}
+
+	inFlight := make(chan struct{}, t.config.MaxInFlight)
+	reader := bufio.NewReader(conn)
+	for {
+		frame, err := readFrame(reader, t.config.Codec)
+		if err != nil {
+			if err != io.EOF && !t.isClosed() {
+				logger.Errorf(err, "Transport: Connection from %s failed.", conn.RemoteAddr())
+			}
+			return
+		}
+		if frame.Kind != frameMessage {
+			continue
+		}
+		var rejectErr error
+		if !t.exposed[frame.ServiceID] {
+			logger.Warnf("Transport: Service %s is not exposed, command %q dropped.", frame.ServiceID, frame.Command)
+			rejectErr = &NoSuchServiceError{ServiceID: frame.ServiceID}
+		} else if reservedCommands[frame.Command] {
+			logger.Warnf("Transport: Command %q to %s is reserved, dropped.", frame.Command, frame.ServiceID)
+			rejectErr = ErrReservedCommand
+		}
+		if rejectErr != nil {
+			if frame.ID != 0 {
+				reply(&transportFrame{
+					Kind:      frameReply,
+					ID:        frame.ID,
+					ServiceID: frame.ServiceID,
+					Command:   frame.Command,
+					Error:     rejectErr.Error(),
+				})
+			}
+			continue
+		}
+		if frame.ID == 0 {
+			if err := router.ForwardE(frame.ServiceID, frame.Command, frame.Params); err != nil {
+				logger.Errorf(err, "Transport: Command %q to %s failed.", frame.Command, frame.ServiceID)
+			}
+			continue
+		}
+		timeout := frame.Timeout
+		if timeout <= 0 || timeout > t.config.MaxTimeout {
+			timeout = t.config.MaxTimeout
+		}
+		inFlight <- struct{}{}
+		go func(frame *transportFrame) {
+			defer func() { <-inFlight }()
+			result, err := router.Request(frame.ServiceID, frame.Command, frame.Params, timeout)
+			response := &transportFrame{
+				Kind:      frameReply,
+				ID:        frame.ID,
+				ServiceID: frame.ServiceID,
+				Command:   frame.Command,
+				Result:    result,
+			}
+			if err != nil {
+				response.Error = err.Error()
+			}
+			reply(response)
+		}(frame)
+	}
+}
+
+// TransportConfig defines how a TransportClient connects to its server.
+//
+// Available since v0.11.0
+type TransportConfig struct {
+	// Codec used to encode frames, must match the server. Default to JSONCodec.
+	Codec Codec
+	// Time to wait for a connection and for results of requests. Default to 30 seconds.
+	Timeout time.Duration
+	// Delay before the first reconnection attempt, doubled after each failure. Default to 100 milliseconds.
+	ReconnectMin time.Duration
+	// Maximum delay between reconnection attempts. Default to 5 seconds.
+	ReconnectMax time.Duration
+}
+
+// TransportClient connects to a TransportServer and reconnects automatically when the connection is lost.
+//
+// Available since v0.11.0
+type TransportClient struct {
+	lastID  uint64
+	network string
+	address string
+	config  TransportConfig
+	logger  diag.Logger
+
+	mu      sync.Mutex
+	conn    net.Conn
+	ready   chan struct{}
+	pending map[uint64]*pendingRequest
+	closed  bool
+	done    chan struct{}
+	writeMu sync.Mutex
+}
+
+// Return new TransportClient connecting to the network address in a separate routine.
+//
+// Available since v0.11.0
+func DialTransport(network, address string, config TransportConfig, logger diag.Logger) *TransportClient {
+	if config.Codec == nil {
+		config.Codec = JSONCodec{}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.ReconnectMin <= 0 {
+		config.ReconnectMin = 100 * time.Millisecond
+	}
+	if config.ReconnectMax < config.ReconnectMin {
+		config.ReconnectMax = 5 * time.Second
+		if config.ReconnectMax < config.ReconnectMin {
+			config.ReconnectMax = config.ReconnectMin
+		}
+	}
+	c := &TransportClient{
+		network: network,
+		address: address,
+		config:  config,
+		logger:  logger,
+		ready:   make(chan struct{}),
+		pending: make(map[uint64]*pendingRequest),
+		done:    make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// Return whether the client is currently connected.
+//
+// Available since v0.11.0
+func (c *TransportClient) Connected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+// Send the message to the remote service without waiting for its result.
+// If the client is disconnected, it waits for the connection up to the configured Timeout.
+//
+// Available since v0.11.0
+func (c *TransportClient) Send(serviceID, command string, params ExecParams) error {
+	return c.send(&transportFrame{
+		Kind:      frameMessage,
+		ServiceID: serviceID,
+		Command:   command,
+		Params:    serializableParams(params),
+	})
+}
+
+// Send the request to the remote service and wait for its result.
+// RequestTimeoutError is returned if the result doesn't arrive within timeout,
+// non-positive timeout means the configured Timeout.
+// Errors returned by the remote service are reported as RemoteError.
+//
+// Available since v0.11.0
+func (c *TransportClient) Request(serviceID, command string, params ExecParams, timeout time.Duration) (interface{}, error) {
+	type reply struct {
+		result interface{}
+		err    error
+	}
+	replyChan := make(chan reply, 1)
+	c.requestAsync(serviceID, command, params, timeout, func(result interface{}, err error) {
+		replyChan <- reply{result, err}
+	})
+	r := <-replyChan
+	return r.result, r.err
+}
+
+// Close the connection and stop reconnecting. Pending requests fail with ErrDisconnected.
+//
+// Available since v0.11.0
+func (c *TransportClient) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	conn := c.conn
+	c.mu.Unlock()
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// Send the request to the remote service, deliver is called exactly once with its outcome.
+func (c *TransportClient) requestAsync(serviceID, command string, params ExecParams, timeout time.Duration, deliver func(result interface{}, err error)) {
+	if timeout <= 0 {
+		timeout = c.config.Timeout
+	}
+	id := atomic.AddUint64(&c.lastID, 1)
+	req := &pendingRequest{
+		serviceID: serviceID,
+		command:   command,
+		deliver: func(_ string, result interface{}, err error) {
+			deliver(result, err)
+		},
+	}
+	c.mu.Lock()
+	c.pending[id] = req
+	req.timer = time.AfterFunc(timeout, func() {
+		c.finish(id, nil, &RequestTimeoutError{
+			ServiceID:     serviceID,
+			Command:       command,
+			CorrelationID: params.CorrelationID(),
+			Timeout:       timeout,
+		})
+	})
+	c.mu.Unlock()
+	err := c.send(&transportFrame{
+		Kind:      frameMessage,
+		ID:        id,
+		ServiceID: serviceID,
+		Command:   command,
+		Params:    serializableParams(params),
+		Timeout:   timeout,
+	})
+	if err != nil {
+		c.finish(id, nil, err)
+	}
+}
+
+// Deliver the outcome of the request exactly once.
+func (c *TransportClient) finish(id uint64, result interface{}, err error) {
+	c.mu.Lock()
+	req, ok := c.pending[id]
+	if ok {
+		delete(c.pending, id)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	req.timer.Stop()
+	req.deliver("", result, err)
+}
+
+// Encode and write the frame once connected.
+func (c *TransportClient) send(frame *transportFrame) error {
+	data, err := encodeFrame(c.config.Codec, frame)
+	if err != nil {
+		return err
+	}
+	conn, err := c.connection()
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err = conn.Write(data)
+	return err
+}
+
+// Return the current connection, waiting for it up to the configured Timeout.
+func (c *TransportClient) connection() (net.Conn, error) {
+	timer := time.NewTimer(c.config.Timeout)
+	defer timer.Stop()
+	for {
+		c.mu.Lock()
+		conn, ready, closed := c.conn, c.ready, c.closed
+		c.mu.Unlock()
+		if closed {
+			return nil, ErrDisconnected
+		}
+		if conn != nil {
+			return conn, nil
+		}
+		select {
+		case <-ready:
+		case <-c.done:
+		case <-timer.C:
+			return nil, ErrDisconnected
+		}
+	}
+}
+
+// Connect to the server and read replies, reconnecting with exponential backoff until closed.
+func (c *TransportClient) run() {
+	delay := c.config.ReconnectMin
+	for {
+		conn, err := net.DialTimeout(c.network, c.address, c.config.Timeout)
+		if err != nil {
+			c.logger.Warnf("Transport: Failed to connect to %s, retrying in %v.", c.address, delay)
+			select {
+			case <-time.After(delay):
+			case <-c.done:
+				return
+			}
+			delay *= 2
+			if delay > c.config.ReconnectMax {
+				delay = c.config.ReconnectMax
+			}
+			continue
+		}
+		delay = c.config.ReconnectMin
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			conn.Close()
+			return
+		}
+		c.conn = conn
+		close(c.ready)
+		c.mu.Unlock()
+		c.logger.Infof("Transport: Connected to %s.", c.address)
+
+		c.readReplies(conn)
+
+		c.mu.Lock()
+		c.conn = nil
+		c.ready = make(chan struct{})
+		closed := c.closed
+		pending := make([]uint64, 0, len(c.pending))
+		for id := range c.pending {
+			pending = append(pending, id)
+		}
+		c.mu.Unlock()
+		conn.Close()
+		for _, id := range pending {
+			c.finish(id, nil, ErrDisconnected)
+		}
+		if closed {
+			return
+		}
+		c.logger.Warnf("Transport: Disconnected from %s.", c.address)
+	}
+}
+
+// Deliver replies received from the connection until it fails.
+func (c *TransportClient) readReplies(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	for {
+		frame, err := readFrame(reader, c.config.Codec)
+		if err != nil {
+			return
+		}
+		if frame.Kind != frameReply {
+			continue
+		}
+		if frame.Error != "" {
+			c.finish(frame.ID, nil, &RemoteError{
+				ServiceID: frame.ServiceID,
+				Command:   frame.Command,
+				Message:   frame.Error,
+			})
+			continue
+		}
+		c.finish(frame.ID, frame.Result, nil)
+	}
+}
+
+// RemoteService is a local proxy forwarding its messages to a service in another process.
+// Results are returned to senders expecting returns. A Process routine waits for the result
+// of each request up to the Timeout of the client, so the outcome is observed like local processing.
+// Use SetWorker to send more requests concurrently.
+//
+// Available since v0.11.0
+type RemoteService struct {
+	ServiceCore
+	i        *ServiceCoreInternal
+	remoteID string
+	client   *TransportClient
+}
+
+// Return new RemoteService forwarding messages to remoteServiceID via the client.
+//
+// Available since v0.11.0
+func NewRemoteService(serviceID, remoteServiceID string, client *TransportClient, logger diag.Logger) *RemoteService {
+	svc := &RemoteService{
+		remoteID: remoteServiceID,
+		client:   client,
+	}
+	svc.i = svc.InitServiceCore(serviceID, logger, svc.coreProcessHook)
+	return svc
+}
+
+// Register a RemoteService forwarding messages for serviceID to remoteServiceID via the client,
+// and start its Process routine.
+//
+// Available since v0.11.0
+func (s *ServiceController) RegisterRemote(serviceID, remoteServiceID string, client *TransportClient) *RemoteService {
+	svc := NewRemoteService(serviceID, remoteServiceID, client, s.i.Logger)
+	svc.SetRouter(s)
+	s.Register(svc)
+	svc.SetWorker(1)
+	return svc
+}
+
+// Forward the message to the remote service.
+func (s *RemoteService) coreProcessHook(workerID uint64, msg *ServiceMessage) *HookState {
+	if msg.Command == "exit" {
+		return &HookState{Handled: false}
+	}
+	var err error
+	if msg.Params != nil && msg.Params["return"] != nil {
+		var result interface{}
+		result, err = s.client.Request(s.remoteID, msg.Command, msg.Params, 0)
+		if err != nil {
+			msg.ReturnError(err)
+		} else {
+			msg.Return(result)
+		}
+	} else {
+		err = s.client.Send(s.remoteID, msg.Command, msg.Params)
+	}
+	if err != nil {
+		s.i.Logger.Errorf(err, "%s#%d: Command %q to %s failed.", s.i.ServiceID, workerID, msg.Command, s.client.address)
+	}
+	return &HookState{Handled: true, Error: err}
+}
+
+// Encode the frame with length prefix.
+func encodeFrame(codec Codec, frame *transportFrame) ([]byte, error) {
+	data, err := codec.Marshal(frame)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes exceeds maximum size", len(data))
+	}
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	return buf, nil
+}
+
+// Read a length prefixed frame.
+func readFrame(reader io.Reader, codec Codec) (*transportFrame, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size > MaxFrameSize {
+		return nil, fmt.Errorf("frame of %d bytes exceeds maximum size", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, err
+	}
+	frame := &transportFrame{}
+	if err := codec.Unmarshal(data, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
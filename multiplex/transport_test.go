@@ -0,0 +1,235 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package multiplex
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+)
+
+func TestTransport_Request(t *testing.T) {
+	for _, codec := range []Codec{JSONCodec{}, GobCodec{}} {
+		t.Run(codec.Name(), func(t *testing.T) {
+			server, _ := newTransportTestController()
+			defer server.SetWorker(0)
+			transport := NewTransportServer(server, TransportServerConfig{Codec: codec}, "Upper")
+			listener, err := transport.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+			defer transport.Close()
+
+			logger := diag.NewDebugLogger(10)
+			client := DialTransport("tcp", listener.Addr().String(), TransportConfig{Codec: codec, Timeout: time.Second}, logger)
+			defer client.Close()
+			result, err := client.Request("Upper", "upper", ExecParams{"text": "hello"}, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "HELLO", result)
+
+			_, err = client.Request("Upper", "fail", ExecParams{"text": "hello"}, 0)
+			var remoteErr *RemoteError
+			assert.True(t, errors.As(err, &remoteErr))
+			assert.Equal(t, "cannot process hello", remoteErr.Message)
+
+			_, err = client.Request("Hidden", "upper", ExecParams{}, 0)
+			assert.True(t, errors.As(err, &remoteErr))
+			assert.Contains(t, remoteErr.Message, "no such service")
+
+			_, err = client.Request("Upper", "exit", ExecParams{}, 0)
+			assert.True(t, errors.As(err, &remoteErr))
+			assert.Equal(t, ErrReservedCommand.Error(), remoteErr.Message)
+			result, err = client.Request("Upper", "upper", ExecParams{"text": "still running"}, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "STILL RUNNING", result)
+		})
+	}
+}
+
+func TestTransport_RemoteService(t *testing.T) {
+	server, upper := newTransportTestController()
+	defer server.SetWorker(0)
+	transport := NewTransportServer(server, TransportServerConfig{}, "Upper")
+	path := filepath.Join(t.TempDir(), "multiplex.sock")
+	_, err := transport.Listen("unix", path)
+	assert.NoError(t, err)
+	defer transport.Close()
+
+	logger := diag.NewDebugLogger(10)
+	client := DialTransport("unix", path, TransportConfig{Timeout: time.Second}, logger)
+	defer client.Close()
+	local := NewServiceController(logger)
+	local.SetWorker(1)
+	defer local.SetWorker(0)
+	proxy := local.RegisterRemote("RemoteUpper", "Upper", client)
+	defer proxy.SetWorker(0)
+
+	result, err := local.Router().Request("RemoteUpper", "upper", ExecParams{"text": "proxy"}, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "PROXY", result)
+
+	_, lastErr := proxy.LastError()
+	assert.NoError(t, lastErr)
+	_, err = local.Router().Request("RemoteUpper", "fail", ExecParams{"text": "proxy"}, time.Second)
+	var remoteErr *RemoteError
+	assert.True(t, errors.As(err, &remoteErr))
+	_, lastErr = proxy.LastError()
+	assert.True(t, errors.As(lastErr, &remoteErr), "outcome of remote request must be observed")
+
+	local.Router().Forward("RemoteUpper", "record", ExecParams{"text": "one-way"})
+	assert.True(t, waitCondition(func() bool { return len(upper.all()) == 1 }))
+	assert.Equal(t, []string{"one-way"}, upper.all())
+}
+
+func TestTransport_Reconnect(t *testing.T) {
+	server, _ := newTransportTestController()
+	defer server.SetWorker(0)
+	path := filepath.Join(t.TempDir(), "multiplex.sock")
+	transport := NewTransportServer(server, TransportServerConfig{}, "Upper")
+	_, err := transport.Listen("unix", path)
+	assert.NoError(t, err)
+
+	logger := diag.NewDebugLogger(10)
+	client := DialTransport("unix", path, TransportConfig{Timeout: time.Second, ReconnectMin: 10 * time.Millisecond}, logger)
+	defer client.Close()
+	result, err := client.Request("Upper", "upper", ExecParams{"text": "before"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "BEFORE", result)
+
+	transport.Close()
+	assert.True(t, waitCondition(func() bool { return !client.Connected() }))
+	transport = NewTransportServer(server, TransportServerConfig{}, "Upper")
+	_, err = transport.Listen("unix", path)
+	assert.NoError(t, err)
+	defer transport.Close()
+
+	result, err = client.Request("Upper", "upper", ExecParams{"text": "after"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "AFTER", result)
+}
+
+func TestTransport_Timeout(t *testing.T) {
+	server, _ := newTransportTestController()
+	defer server.SetWorker(0)
+	transport := NewTransportServer(server, TransportServerConfig{}, "Upper")
+	listener, err := transport.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer transport.Close()
+
+	client := DialTransport("tcp", listener.Addr().String(), TransportConfig{}, diag.NewDebugLogger(10))
+	defer client.Close()
+	_, err = client.Request("Upper", "ignore", ExecParams{}, 20*time.Millisecond)
+	assert.True(t, errors.Is(err, ErrRequestTimeout))
+
+	client.Close()
+	_, err = client.Request("Upper", "upper", ExecParams{"text": "closed"}, 0)
+	assert.True(t, errors.Is(err, ErrDisconnected))
+}
+
+func TestTransport_ServerLimits(t *testing.T) {
+	server, _ := newTransportTestController()
+	defer server.SetWorker(0)
+	transport := NewTransportServer(server, TransportServerConfig{MaxTimeout: 50 * time.Millisecond, MaxInFlight: 1}, "Upper")
+	listener, err := transport.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer transport.Close()
+
+	client := DialTransport("tcp", listener.Addr().String(), TransportConfig{Timeout: 5 * time.Second}, diag.NewDebugLogger(10))
+	defer client.Close()
+	start := time.Now()
+	_, err = client.Request("Upper", "ignore", ExecParams{}, time.Minute)
+	var remoteErr *RemoteError
+	assert.True(t, errors.As(err, &remoteErr), "timeout requested by peer must be capped")
+	assert.Contains(t, remoteErr.Message, "timed out")
+	assert.Less(t, time.Since(start), time.Second)
+
+	ignored := make(chan error, 1)
+	client.requestAsync("Upper", "ignore", ExecParams{}, 0, func(_ interface{}, err error) {
+		ignored <- err
+	})
+	time.Sleep(10 * time.Millisecond)
+	start = time.Now()
+	result, err := client.Request("Upper", "upper", ExecParams{"text": "queued"}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "QUEUED", result)
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond, "request must wait for a free slot")
+	assert.Error(t, <-ignored)
+}
+
+func TestTransport_NothingExposed(t *testing.T) {
+	server, _ := newTransportTestController()
+	defer server.SetWorker(0)
+	transport := NewTransportServer(server, TransportServerConfig{})
+	listener, err := transport.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer transport.Close()
+
+	client := DialTransport("tcp", listener.Addr().String(), TransportConfig{Timeout: time.Second}, diag.NewDebugLogger(10))
+	defer client.Close()
+	_, err = client.Request("Upper", "upper", ExecParams{"text": "hello"}, 0)
+	var remoteErr *RemoteError
+	assert.True(t, errors.As(err, &remoteErr))
+	assert.Contains(t, remoteErr.Message, "no such service")
+}
+
+func newTransportTestController() (*ServiceController, *UpperService) {
+	logger := diag.NewDebugLogger(10)
+	controller := NewServiceController(logger)
+	for _, serviceID := range []string{"Upper", "Hidden"} {
+		svc := NewUpperService(serviceID, logger)
+		svc.SetRouter(controller)
+		controller.Register(svc)
+		svc.SetWorker(1)
+	}
+	controller.SetWorker(1)
+	upper, _ := controller.Service("Upper")
+	return controller, upper.(*UpperService)
+}
+
+type UpperService struct {
+	ServiceCore
+	i *ServiceCoreInternal
+
+	mu       sync.Mutex
+	recorded []string
+}
+
+func NewUpperService(serviceID string, logger diag.Logger) *UpperService {
+	svc := &UpperService{}
+	svc.i = svc.InitServiceCore(serviceID, logger, svc.coreProcessHook)
+	return svc
+}
+
+func (s *UpperService) coreProcessHook(workerID uint64, msg *ServiceMessage) *HookState {
+	text, _ := msg.GetParam("text", "").(string)
+	switch msg.Command {
+	case "upper":
+		msg.Return(strings.ToUpper(text))
+	case "fail":
+		msg.ReturnError(errors.New("cannot process " + text))
+	case "record":
+		s.mu.Lock()
+		s.recorded = append(s.recorded, text)
+		s.mu.Unlock()
+	case "relay":
+		s.Dispatch(msg.GetParam("target", "").(string), "record", ExecParams{"text": text})
+	case "ignore":
+	default:
+		return &HookState{Handled: false}
+	}
+	return &HookState{Handled: true}
+}
+
+func (s *UpperService) all() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.recorded...)
+}
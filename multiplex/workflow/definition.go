@@ -0,0 +1,203 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tforce-io/tf-golib/multiplex"
+)
+
+// ErrInvalidDefinition is returned when a workflow definition is malformed.
+//
+// Available since v0.11.0
+var ErrInvalidDefinition = errors.New("invalid workflow definition")
+
+// ParamsFunc returns the params of a request from the workflow input and the results
+// of completed steps, by step name.
+//
+// Available since v0.11.0
+type ParamsFunc func(input map[string]interface{}, results map[string]interface{}) multiplex.ExecParams
+
+// Compensation is the request undoing the effect of a completed step.
+//
+// Available since v0.11.0
+type Compensation struct {
+	// Service receiving the request. Empty means the service of the step.
+	Service string
+	Command string
+	// Params of the request. If nil, the request only carries the result of the step under multiplex.ResultKey.
+	Params ParamsFunc
+	// Time to wait for the result of each attempt. Non-positive means waiting indefinitely.
+	Timeout time.Duration
+	// Number of attempts after the first one failed.
+	Retries int
+	// Time to wait between attempts.
+	RetryDelay time.Duration
+}
+
+// Step is a request sent by a workflow.
+//
+// Available since v0.11.0
+type Step struct {
+	// Name of the step, unique within the workflow.
+	Name    string
+	Service string
+	Command string
+	// Params of the request. If nil, the request only carries workflow keys.
+	Params ParamsFunc
+	// Steps which must complete before this step starts.
+	DependsOn []string
+	// Time to wait for the result of each attempt. Non-positive means waiting indefinitely.
+	Timeout time.Duration
+	// Number of attempts after the first one failed.
+	Retries int
+	// Time to wait between attempts.
+	RetryDelay time.Duration
+	// Return whether a failed attempt should be retried. Nil means all errors are retried.
+	Retryable func(err error) bool
+	// Request undoing the step when a later step fails, nil if the step needs no compensation.
+	// It is also sent if the step failed after an attempt timed out, since the request may still take effect.
+	Compensate *Compensation
+}
+
+// Struct Definition is a validated workflow. It is immutable and safe for concurrent use.
+//
+// Available since v0.11.0
+type Definition struct {
+	name  string
+	steps []Step
+	index map[string]int
+}
+
+// Return new Definition of a workflow whose steps form a DAG.
+// Steps without dependencies start immediately, other steps start once all their dependencies completed.
+// ErrInvalidDefinition is returned if names are missing or duplicated,
+// a dependency is unknown or dependencies form a cycle.
+//
+// Available since v0.11.0
+func NewDefinition(name string, steps ...Step) (*Definition, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: workflow name is empty", ErrInvalidDefinition)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("%w: workflow %q has no step", ErrInvalidDefinition, name)
+	}
+	def := &Definition{
+		name:  name,
+		steps: make([]Step, len(steps)),
+		index: make(map[string]int, len(steps)),
+	}
+	for i, step := range steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("%w: step #%d of workflow %q has no name", ErrInvalidDefinition, i, name)
+		}
+		if step.Service == "" || step.Command == "" {
+			return nil, fmt.Errorf("%w: step %q has no service or command", ErrInvalidDefinition, step.Name)
+		}
+		if step.Compensate != nil && step.Compensate.Command == "" {
+			return nil, fmt.Errorf("%w: compensation of step %q has no command", ErrInvalidDefinition, step.Name)
+		}
+		if _, found := def.index[step.Name]; found {
+			return nil, fmt.Errorf("%w: step %q is duplicated", ErrInvalidDefinition, step.Name)
+		}
+		step.DependsOn = append([]string(nil), step.DependsOn...)
+		def.steps[i] = step
+		def.index[step.Name] = i
+	}
+	for _, step := range def.steps {
+		for _, dependency := range step.DependsOn {
+			if _, found := def.index[dependency]; !found {
+				return nil, fmt.Errorf("%w: step %q depends on unknown step %q", ErrInvalidDefinition, step.Name, dependency)
+			}
+		}
+	}
+	if err := def.checkCycle(); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// Return new Definition of a workflow running steps one after another.
+// Each step depends on the previous one in addition to its own dependencies.
+//
+// Available since v0.11.0
+func Sequence(name string, steps ...Step) (*Definition, error) {
+	chained := make([]Step, len(steps))
+	for i, step := range steps {
+		if i > 0 {
+			step.DependsOn = append([]string{steps[i-1].Name}, step.DependsOn...)
+		}
+		chained[i] = step
+	}
+	return NewDefinition(name, chained...)
+}
+
+// Return name of the workflow.
+//
+// Available since v0.11.0
+func (d *Definition) Name() string {
+	return d.name
+}
+
+// Return steps of the workflow in definition order.
+//
+// Available since v0.11.0
+func (d *Definition) Steps() []Step {
+	return append([]Step(nil), d.steps...)
+}
+
+// Return the step with specified name.
+//
+// Available since v0.11.0
+func (d *Definition) Step(name string) (Step, bool) {
+	if i, found := d.index[name]; found {
+		return d.steps[i], true
+	}
+	return Step{}, false
+}
+
+// Return ErrInvalidDefinition if dependencies form a cycle.
+func (d *Definition) checkCycle() error {
+	remaining := make([]int, len(d.steps))
+	dependents := make([][]int, len(d.steps))
+	for i, step := range d.steps {
+		remaining[i] = len(step.DependsOn)
+		for _, dependency := range step.DependsOn {
+			j := d.index[dependency]
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+	var ready []int
+	for i, count := range remaining {
+		if count == 0 {
+			ready = append(ready, i)
+		}
+	}
+	visited := 0
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		visited++
+		for _, j := range dependents[i] {
+			remaining[j]--
+			if remaining[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+	if visited < len(d.steps) {
+		for i, count := range remaining {
+			if count > 0 {
+				return fmt.Errorf("%w: step %q depends on a dependency cycle", ErrInvalidDefinition, d.steps[i].Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDefinition(t *testing.T) {
+	def, err := NewDefinition("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve"},
+		Step{Name: "charge", Service: "Shop", Command: "charge"},
+		Step{Name: "ship", Service: "Shop", Command: "ship", DependsOn: []string{"reserve", "charge"}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "order", def.Name())
+	assert.Len(t, def.Steps(), 3)
+	step, found := def.Step("ship")
+	assert.True(t, found)
+	assert.Equal(t, []string{"reserve", "charge"}, step.DependsOn)
+	_, found = def.Step("refund")
+	assert.False(t, found)
+}
+
+func TestNewDefinition_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		workflow string
+		steps    []Step
+	}{
+		{"no name", "", []Step{{Name: "a", Service: "S", Command: "c"}}},
+		{"no step", "w", nil},
+		{"no step name", "w", []Step{{Service: "S", Command: "c"}}},
+		{"no command", "w", []Step{{Name: "a", Service: "S"}}},
+		{"no compensation command", "w", []Step{{Name: "a", Service: "S", Command: "c", Compensate: &Compensation{}}}},
+		{"duplicated", "w", []Step{{Name: "a", Service: "S", Command: "c"}, {Name: "a", Service: "S", Command: "c"}}},
+		{"unknown dependency", "w", []Step{{Name: "a", Service: "S", Command: "c", DependsOn: []string{"b"}}}},
+		{"cycle", "w", []Step{
+			{Name: "a", Service: "S", Command: "c", DependsOn: []string{"b"}},
+			{Name: "b", Service: "S", Command: "c", DependsOn: []string{"a"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDefinition(tt.workflow, tt.steps...)
+			assert.ErrorIs(t, err, ErrInvalidDefinition)
+		})
+	}
+}
+
+func TestSequence(t *testing.T) {
+	def, err := Sequence("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve"},
+		Step{Name: "charge", Service: "Shop", Command: "charge"},
+		Step{Name: "ship", Service: "Shop", Command: "ship", DependsOn: []string{"reserve"}},
+	)
+	assert.NoError(t, err)
+	steps := def.Steps()
+	assert.Empty(t, steps[0].DependsOn)
+	assert.Equal(t, []string{"reserve"}, steps[1].DependsOn)
+	assert.Equal(t, []string{"charge", "reserve"}, steps[2].DependsOn)
+}
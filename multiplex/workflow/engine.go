@@ -0,0 +1,610 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tforce-io/tf-golib/diag"
+	"github.com/tforce-io/tf-golib/multiplex"
+	"github.com/tforce-io/tf-golib/random/securerng"
+)
+
+const (
+	// Parameter key holding the ID of the workflow sending a request.
+	//
+	// Available since v0.11.0
+	WorkflowIDKey = "workflow_id"
+
+	// Parameter key holding the name of the step sending a request.
+	//
+	// Available since v0.11.0
+	StepKey = "workflow_step"
+)
+
+// ErrUnknownWorkflow is returned when starting a workflow whose Definition is not registered.
+//
+// Available since v0.11.0
+var ErrUnknownWorkflow = errors.New("unknown workflow")
+
+// Config stores options of an Engine.
+//
+// Available since v0.11.0
+type Config struct {
+	// Store persisting states after each transition, nil if states are only kept in memory.
+	Store Store
+	// Keep states of finished workflows in Store. By default they are deleted once finished.
+	KeepFinished bool
+	Logger       diag.Logger
+}
+
+// Struct Engine runs workflows by sending their requests via a ServiceRouter.
+// Each request carries WorkflowIDKey, StepKey and a multiplex.IdempotencyKey unique to the step
+// unless params already define it, so services deduplicating requests process each step once
+// even if it is retried or resumed.
+//
+// Available since v0.11.0
+type Engine struct {
+	router       *multiplex.ServiceRouter
+	store        Store
+	keepFinished bool
+	logger       diag.Logger
+
+	mu          sync.Mutex
+	definitions map[string]*Definition
+	executions  map[string]*Execution
+}
+
+// Return new Engine sending requests via router.
+//
+// Available since v0.11.0
+func NewEngine(router *multiplex.ServiceRouter, config Config) *Engine {
+	return &Engine{
+		router:       router,
+		store:        config.Store,
+		keepFinished: config.KeepFinished,
+		logger:       config.Logger,
+		definitions:  make(map[string]*Definition),
+		executions:   make(map[string]*Execution),
+	}
+}
+
+// Register the Definition of a workflow, replacing any Definition with the same name.
+//
+// Available since v0.11.0
+func (e *Engine) Register(def *Definition) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.definitions[def.name] = def
+}
+
+// Start a new instance of the workflow with specified name.
+// Cancelling ctx stops starting new steps, then completed steps are compensated
+// once running steps finished. ErrUnknownWorkflow is returned if the workflow is not registered.
+//
+// Available since v0.11.0
+func (e *Engine) Start(ctx context.Context, name string, input map[string]interface{}) (*Execution, error) {
+	e.mu.Lock()
+	def, found := e.definitions[name]
+	e.mu.Unlock()
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWorkflow, name)
+	}
+	now := time.Now()
+	state := State{
+		ID:       securerng.Hex(8),
+		Workflow: name,
+		Status:   StatusRunning,
+		Input:    input,
+		Steps:    make([]StepState, len(def.steps)),
+		Started:  now,
+		Updated:  now,
+	}
+	for i, step := range def.steps {
+		state.Steps[i] = StepState{Name: step.Name, Status: StepPending}
+	}
+	return e.start(ctx, def, state), nil
+}
+
+// Resume unfinished workflows persisted in the Store, usually after restart.
+// Steps running at the time the state was saved are sent again, as well as compensations in progress.
+// Workflows whose Definition is not registered or already running are skipped.
+// States loaded from a FileStore hold Input and step results as decoded by encoding/json,
+// numbers become float64 and structs become map[string]interface{}.
+//
+// Available since v0.11.0
+func (e *Engine) Resume(ctx context.Context) ([]*Execution, error) {
+	if e.store == nil {
+		return nil, nil
+	}
+	states, err := e.store.List()
+	if err != nil {
+		return nil, err
+	}
+	var executions []*Execution
+	for _, state := range states {
+		if state.Status.Terminal() {
+			continue
+		}
+		e.mu.Lock()
+		def, found := e.definitions[state.Workflow]
+		_, running := e.executions[state.ID]
+		e.mu.Unlock()
+		if running {
+			continue
+		}
+		if !found {
+			e.warnf("Workflow %s: Definition %q is not registered, resume skipped.", state.ID, state.Workflow)
+			continue
+		}
+		resumed, err := resumeState(def, state)
+		if err != nil {
+			e.warnf("Workflow %s: %v, resume skipped.", state.ID, err)
+			continue
+		}
+		e.infof("Workflow %s: Resumed in %s state.", state.ID, state.Status)
+		executions = append(executions, e.start(ctx, def, resumed))
+	}
+	return executions, nil
+}
+
+// Return the running instance of a workflow.
+//
+// Available since v0.11.0
+func (e *Engine) Execution(id string) (*Execution, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	execution, found := e.executions[id]
+	return execution, found
+}
+
+// Return states of running workflows ordered by start time.
+//
+// Available since v0.11.0
+func (e *Engine) States() []State {
+	e.mu.Lock()
+	executions := make([]*Execution, 0, len(e.executions))
+	for _, execution := range e.executions {
+		executions = append(executions, execution)
+	}
+	e.mu.Unlock()
+	states := make([]State, len(executions))
+	for i, execution := range executions {
+		states[i] = execution.State()
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Started.Equal(states[j].Started) {
+			return states[i].ID < states[j].ID
+		}
+		return states[i].Started.Before(states[j].Started)
+	})
+	return states
+}
+
+// Track and run an instance of a workflow.
+func (e *Engine) start(ctx context.Context, def *Definition, state State) *Execution {
+	ctx, cancel := context.WithCancel(ctx)
+	x := &Execution{
+		engine: e,
+		def:    def,
+		state:  state,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	for _, step := range state.Steps {
+		if step.Completed > x.completed {
+			x.completed = step.Completed
+		}
+	}
+	e.mu.Lock()
+	e.executions[state.ID] = x
+	e.mu.Unlock()
+	x.update(func(state *State) {})
+	go x.run(ctx)
+	return x
+}
+
+func (e *Engine) infof(format string, v ...interface{}) {
+	if e.logger != nil {
+		e.logger.Infof(format, v...)
+	}
+}
+
+func (e *Engine) warnf(format string, v ...interface{}) {
+	if e.logger != nil {
+		e.logger.Warnf(format, v...)
+	}
+}
+
+// Return state aligned with the steps of def, ready to continue.
+func resumeState(def *Definition, persisted State) (State, error) {
+	state := persisted.clone()
+	state.Steps = make([]StepState, len(def.steps))
+	for i, step := range def.steps {
+		previous, found := persisted.Step(step.Name)
+		if !found {
+			return State{}, fmt.Errorf("step %q is not persisted", step.Name)
+		}
+		if previous.Status == StepRunning {
+			previous.Status = StepPending
+		}
+		state.Steps[i] = previous
+	}
+	return state, nil
+}
+
+// Struct Execution is a running instance of a workflow.
+//
+// Available since v0.11.0
+type Execution struct {
+	engine *Engine
+	def    *Definition
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu        sync.Mutex
+	state     State
+	completed int
+	// Error causing the workflow to fail, nil if it was resumed from a persisted state.
+	failure error
+	// Error returned by Wait, available once done is closed.
+	err error
+}
+
+// outcome is the result of a step.
+type outcome struct {
+	step     string
+	result   interface{}
+	err      error
+	timedOut bool
+}
+
+// Return ID of the workflow instance.
+//
+// Available since v0.11.0
+func (x *Execution) ID() string {
+	return x.state.ID
+}
+
+// Return a snapshot of the state of the workflow.
+//
+// Available since v0.11.0
+func (x *Execution) State() State {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.state.clone()
+}
+
+// Return a channel closed when the workflow has finished.
+//
+// Available since v0.11.0
+func (x *Execution) Done() <-chan struct{} {
+	return x.done
+}
+
+// Wait until the workflow has finished and return its final state.
+// StepError is returned if the workflow failed, whether compensations succeeded or not.
+// Return the error of ctx if it is done before that.
+//
+// Available since v0.11.0
+func (x *Execution) Wait(ctx context.Context) (State, error) {
+	select {
+	case <-x.done:
+		return x.State(), x.err
+	case <-ctx.Done():
+		return State{}, ctx.Err()
+	}
+}
+
+// Stop starting new steps, then compensate completed steps once running steps finished.
+//
+// Available since v0.11.0
+func (x *Execution) Cancel() {
+	x.cancel()
+}
+
+// Run the workflow until it finishes.
+func (x *Execution) run(ctx context.Context) {
+	defer x.cancel()
+	if x.State().Status == StatusRunning {
+		x.forward(ctx)
+	}
+	if x.State().Status == StatusCompensating {
+		x.compensate()
+	}
+	state := x.State()
+	x.engine.mu.Lock()
+	delete(x.engine.executions, state.ID)
+	x.engine.mu.Unlock()
+	x.forget()
+	if state.Status == StatusCompleted {
+		x.engine.infof("Workflow %s: Completed.", state.ID)
+		close(x.done)
+		return
+	}
+	x.engine.warnf("Workflow %s: Finished in %s state.", state.ID, state.Status)
+	x.mu.Lock()
+	err := x.failure
+	x.mu.Unlock()
+	if err == nil {
+		err = errors.New(state.Error)
+	}
+	x.err = &StepError{
+		WorkflowID: state.ID,
+		Step:       state.FailedStep,
+		Err:        err,
+	}
+	close(x.done)
+}
+
+// Execute steps as soon as their dependencies completed, until all steps completed or a step failed.
+func (x *Execution) forward(ctx context.Context) {
+	outcomes := make(chan outcome)
+	running := 0
+	var failure *outcome
+	for {
+		if failure == nil && ctx.Err() == nil {
+			for _, step := range x.ready() {
+				running++
+				go func(step Step) {
+					result, timedOut, err := x.execute(ctx, step)
+					outcomes <- outcome{step: step.Name, result: result, err: err, timedOut: timedOut}
+				}(step)
+			}
+		}
+		if running == 0 {
+			break
+		}
+		o := <-outcomes
+		running--
+		x.update(func(state *State) {
+			now := time.Now()
+			step := state.step(o.step)
+			step.Finished = &now
+			if o.err != nil {
+				step.Status = StepFailed
+				if o.timedOut {
+					step.Status = StepTimedOut
+				}
+				step.Error = o.err.Error()
+				return
+			}
+			x.completed++
+			step.Status = StepCompleted
+			step.Result = o.result
+			step.Error = ""
+			step.Completed = x.completed
+		})
+		if o.err != nil {
+			x.engine.warnf("Workflow %s: Step %s failed: %v", x.state.ID, o.step, o.err)
+			if failure == nil {
+				failure = &o
+			}
+		}
+	}
+	x.update(func(state *State) {
+		if failure != nil {
+			state.Status = StatusCompensating
+			state.FailedStep = failure.step
+			state.Error = failure.err.Error()
+			x.failure = failure.err
+			return
+		}
+		for _, step := range state.Steps {
+			if step.Status == StepFailed || step.Status == StepTimedOut {
+				state.Status = StatusCompensating
+				state.FailedStep = step.Name
+				state.Error = step.Error
+				return
+			}
+		}
+		for _, step := range state.Steps {
+			if step.Status != StepCompleted {
+				err := ctx.Err()
+				if err == nil {
+					err = context.Canceled
+				}
+				state.Status = StatusCompensating
+				state.Error = err.Error()
+				x.failure = err
+				return
+			}
+		}
+		state.Status = StatusCompleted
+	})
+}
+
+// Mark steps whose dependencies completed as running and return them.
+func (x *Execution) ready() []Step {
+	var steps []Step
+	x.update(func(state *State) {
+		now := time.Now()
+		for i, step := range x.def.steps {
+			if state.Steps[i].Status != StepPending {
+				continue
+			}
+			ready := true
+			for _, dependency := range step.DependsOn {
+				if state.step(dependency).Status != StepCompleted {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				state.Steps[i].Status = StepRunning
+				state.Steps[i].Started = &now
+				steps = append(steps, step)
+			}
+		}
+	})
+	return steps
+}
+
+// Send the request of step until it succeeds or attempts are exhausted.
+// Retries stop when ctx is done, but an attempt in progress is never interrupted.
+// Also return whether any attempt timed out, in which case the step may have taken effect.
+func (x *Execution) execute(ctx context.Context, step Step) (interface{}, bool, error) {
+	timedOut := false
+	for attempt := 0; ; attempt++ {
+		var params multiplex.ExecParams
+		x.update(func(state *State) {
+			state.step(step.Name).Attempts++
+			params = x.params(state, step.Name, step.Params, nil, false)
+		})
+		result, err := x.engine.router.RequestFuture(step.Service, step.Command, params, step.Timeout).Await(context.Background())
+		if err == nil {
+			return result, false, nil
+		}
+		if errors.Is(err, multiplex.ErrRequestTimeout) {
+			timedOut = true
+		}
+		if attempt >= step.Retries || (step.Retryable != nil && !step.Retryable(err)) {
+			return nil, timedOut, err
+		}
+		x.engine.warnf("Workflow %s: Step %s attempt #%d failed, retrying: %v", x.state.ID, step.Name, attempt+1, err)
+		if sleep(ctx, step.RetryDelay) != nil {
+			return nil, timedOut, err
+		}
+	}
+}
+
+// Compensate timed out steps first, then completed steps in reverse completion order.
+func (x *Execution) compensate() {
+	state := x.State()
+	var steps []StepState
+	for _, step := range state.Steps {
+		if step.Status == StepCompleted || step.Status == StepTimedOut || step.Status == StepCompensating {
+			steps = append(steps, step)
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		// Timed out steps never completed, they started after the completed steps they depend on.
+		if steps[i].Completed == 0 || steps[j].Completed == 0 {
+			return steps[i].Completed == 0 && steps[j].Completed != 0
+		}
+		return steps[i].Completed > steps[j].Completed
+	})
+	failed := false
+	for _, progress := range steps {
+		step, _ := x.def.Step(progress.Name)
+		if step.Compensate == nil {
+			continue
+		}
+		x.update(func(state *State) {
+			state.step(step.Name).Status = StepCompensating
+		})
+		err := x.undo(step)
+		x.update(func(state *State) {
+			progress := state.step(step.Name)
+			if err != nil {
+				progress.Status = StepCompensationFailed
+				progress.CompensationError = err.Error()
+				return
+			}
+			progress.Status = StepCompensated
+			progress.CompensationError = ""
+		})
+		if err != nil {
+			x.engine.warnf("Workflow %s: Compensation of step %s failed: %v", x.state.ID, step.Name, err)
+			failed = true
+		}
+	}
+	x.update(func(state *State) {
+		if failed {
+			state.Status = StatusFailed
+		} else {
+			state.Status = StatusCompensated
+		}
+	})
+}
+
+// Send the compensation of step until it succeeds or attempts are exhausted.
+func (x *Execution) undo(step Step) error {
+	compensation := step.Compensate
+	service := compensation.Service
+	if service == "" {
+		service = step.Service
+	}
+	for attempt := 0; ; attempt++ {
+		var params multiplex.ExecParams
+		x.update(func(state *State) {
+			params = x.params(state, step.Name, compensation.Params, state.step(step.Name).Result, true)
+		})
+		_, err := x.engine.router.RequestFuture(service, compensation.Command, params, compensation.Timeout).Await(context.Background())
+		if err == nil || attempt >= compensation.Retries {
+			return err
+		}
+		sleep(context.Background(), compensation.RetryDelay)
+	}
+}
+
+// Return params of a request sent by the step with specified name. Must be called within update.
+func (x *Execution) params(state *State, name string, fn ParamsFunc, result interface{}, compensate bool) multiplex.ExecParams {
+	var params multiplex.ExecParams
+	if fn != nil {
+		params = fn(state.Input, state.Results())
+	} else if compensate {
+		params = multiplex.ExecParams{multiplex.ResultKey: result}
+	}
+	if params == nil {
+		params = make(multiplex.ExecParams)
+	}
+	params[WorkflowIDKey] = state.ID
+	params[StepKey] = name
+	if params[multiplex.IdempotencyKey] == nil {
+		key := state.ID + "/" + name
+		if compensate {
+			key += "/compensate"
+		}
+		params[multiplex.IdempotencyKey] = key
+	}
+	return params
+}
+
+// Delete the state of the finished workflow from Store unless finished states are kept.
+func (x *Execution) forget() {
+	if x.engine.store == nil || x.engine.keepFinished {
+		return
+	}
+	if err := x.engine.store.Delete(x.state.ID); err != nil && x.engine.logger != nil {
+		x.engine.logger.Errorf(err, "Workflow %s: Failed to delete state.", x.state.ID)
+	}
+}
+
+// Apply fn to the state then persist it.
+func (x *Execution) update(fn func(state *State)) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	fn(&x.state)
+	x.state.Updated = time.Now()
+	if x.engine.store == nil {
+		return
+	}
+	if err := x.engine.store.Save(x.state.clone()); err != nil && x.engine.logger != nil {
+		x.engine.logger.Errorf(err, "Workflow %s: Failed to save state.", x.state.ID)
+	}
+}
+
+// Wait for delay. Return the error of ctx if it is done before that.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
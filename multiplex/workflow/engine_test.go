@@ -0,0 +1,384 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tforce-io/tf-golib/diag"
+	"github.com/tforce-io/tf-golib/multiplex"
+)
+
+var errOutOfStock = errors.New("out of stock")
+
+func TestEngine_Start(t *testing.T) {
+	shop, engine := newShopEngine(t, nil)
+	def, _ := Sequence("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve", Params: func(input, results map[string]interface{}) multiplex.ExecParams {
+			return multiplex.ExecParams{"item": input["item"]}
+		}},
+		Step{Name: "charge", Service: "Shop", Command: "charge", Params: func(input, results map[string]interface{}) multiplex.ExecParams {
+			return multiplex.ExecParams{"item": results["reserve"]}
+		}},
+	)
+	engine.Register(def)
+
+	execution, err := engine.Start(context.Background(), "order", map[string]interface{}{"item": "book"})
+	assert.NoError(t, err)
+	state, err := execution.Wait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCompleted, state.Status)
+	assert.Equal(t, map[string]interface{}{"reserve": "reserve:book", "charge": "charge:reserve:book"}, state.Results())
+	assert.Equal(t, []string{"reserve", "charge"}, shop.commands())
+
+	params := shop.params("charge")
+	assert.Equal(t, execution.ID(), params[WorkflowIDKey])
+	assert.Equal(t, "charge", params[StepKey])
+	assert.Equal(t, execution.ID()+"/charge", params[multiplex.IdempotencyKey])
+	_, found := engine.Execution(execution.ID())
+	assert.False(t, found, "finished workflow must not be tracked")
+}
+
+func TestEngine_Start_Unknown(t *testing.T) {
+	_, engine := newShopEngine(t, nil)
+	_, err := engine.Start(context.Background(), "missing", nil)
+	assert.ErrorIs(t, err, ErrUnknownWorkflow)
+}
+
+func TestEngine_DAG(t *testing.T) {
+	shop, engine := newShopEngine(t, nil)
+	shop.gate = make(chan struct{})
+	def, _ := NewDefinition("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve"},
+		Step{Name: "charge", Service: "Shop", Command: "charge"},
+		Step{Name: "ship", Service: "Shop", Command: "ship", DependsOn: []string{"reserve", "charge"}},
+	)
+	engine.Register(def)
+
+	execution, _ := engine.Start(context.Background(), "order", nil)
+	assert.Eventually(t, func() bool { return len(shop.commands()) == 2 }, time.Second, time.Millisecond,
+		"independent steps must run in parallel")
+	state := execution.State()
+	assert.Equal(t, StatusRunning, state.Status)
+	ship, _ := state.Step("ship")
+	assert.Equal(t, StepPending, ship.Status)
+	assert.Len(t, engine.States(), 1)
+
+	close(shop.gate)
+	state, err := execution.Wait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCompleted, state.Status)
+	assert.Equal(t, "ship", shop.commands()[2])
+}
+
+func TestEngine_Compensation(t *testing.T) {
+	shop, engine := newShopEngine(t, nil)
+	shop.failures["ship"] = 1
+	def, _ := Sequence("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve", Compensate: &Compensation{Command: "release"}},
+		Step{Name: "notify", Service: "Shop", Command: "notify"},
+		Step{Name: "charge", Service: "Shop", Command: "charge", Compensate: &Compensation{Command: "refund"}},
+		Step{Name: "ship", Service: "Shop", Command: "ship", Compensate: &Compensation{Command: "recall"}},
+	)
+	engine.Register(def)
+
+	execution, _ := engine.Start(context.Background(), "order", nil)
+	state, err := execution.Wait(context.Background())
+	assert.ErrorIs(t, err, errOutOfStock)
+	var stepErr *StepError
+	assert.True(t, errors.As(err, &stepErr))
+	assert.Equal(t, "ship", stepErr.Step)
+	assert.Equal(t, execution.ID(), stepErr.WorkflowID)
+
+	assert.Equal(t, StatusCompensated, state.Status)
+	assert.Equal(t, "ship", state.FailedStep)
+	assert.Equal(t, errOutOfStock.Error(), state.Error)
+	assert.Equal(t, []string{"reserve", "notify", "charge", "ship", "refund", "release"}, shop.commands(),
+		"completed steps must be compensated in reverse order")
+	assert.Equal(t, "charge:", shop.params("refund")[multiplex.ResultKey])
+	assert.Equal(t, execution.ID()+"/charge/compensate", shop.params("refund")[multiplex.IdempotencyKey])
+	for name, status := range map[string]StepStatus{
+		"reserve": StepCompensated,
+		"notify":  StepCompleted,
+		"charge":  StepCompensated,
+		"ship":    StepFailed,
+	} {
+		step, _ := state.Step(name)
+		assert.Equal(t, status, step.Status, name)
+	}
+}
+
+func TestEngine_Compensation_Failed(t *testing.T) {
+	shop, engine := newShopEngine(t, nil)
+	shop.failures["charge"] = 1
+	shop.failures["release"] = 3
+	def, _ := Sequence("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve", Compensate: &Compensation{Command: "release", Retries: 1}},
+		Step{Name: "charge", Service: "Shop", Command: "charge"},
+	)
+	engine.Register(def)
+
+	execution, _ := engine.Start(context.Background(), "order", nil)
+	state, err := execution.Wait(context.Background())
+	assert.ErrorIs(t, err, errOutOfStock)
+	assert.Equal(t, StatusFailed, state.Status)
+	reserve, _ := state.Step("reserve")
+	assert.Equal(t, StepCompensationFailed, reserve.Status)
+	assert.Equal(t, errOutOfStock.Error(), reserve.CompensationError)
+	assert.Equal(t, []string{"reserve", "charge", "release", "release"}, shop.commands())
+}
+
+func TestEngine_Retries(t *testing.T) {
+	shop, engine := newShopEngine(t, nil)
+	shop.failures["charge"] = 2
+	def, _ := Sequence("order",
+		Step{Name: "charge", Service: "Shop", Command: "charge", Retries: 2, RetryDelay: time.Millisecond},
+	)
+	engine.Register(def)
+
+	execution, _ := engine.Start(context.Background(), "order", nil)
+	state, err := execution.Wait(context.Background())
+	assert.NoError(t, err)
+	charge, _ := state.Step("charge")
+	assert.Equal(t, 3, charge.Attempts)
+	assert.Equal(t, StepCompleted, charge.Status)
+}
+
+func TestEngine_Retries_NotRetryable(t *testing.T) {
+	shop, engine := newShopEngine(t, nil)
+	shop.failures["charge"] = 2
+	def, _ := Sequence("order",
+		Step{Name: "charge", Service: "Shop", Command: "charge", Retries: 2, Retryable: func(err error) bool {
+			return !errors.Is(err, errOutOfStock)
+		}},
+	)
+	engine.Register(def)
+
+	execution, _ := engine.Start(context.Background(), "order", nil)
+	state, err := execution.Wait(context.Background())
+	assert.ErrorIs(t, err, errOutOfStock)
+	charge, _ := state.Step("charge")
+	assert.Equal(t, 1, charge.Attempts)
+}
+
+func TestEngine_Timeout(t *testing.T) {
+	_, engine := newShopEngine(t, nil)
+	def, _ := Sequence("order",
+		Step{Name: "wait", Service: "Shop", Command: "hang", Timeout: 20 * time.Millisecond, Retries: 1},
+	)
+	engine.Register(def)
+
+	execution, _ := engine.Start(context.Background(), "order", nil)
+	state, err := execution.Wait(context.Background())
+	assert.ErrorIs(t, err, multiplex.ErrRequestTimeout)
+	step, _ := state.Step("wait")
+	assert.Equal(t, 2, step.Attempts)
+	assert.Equal(t, StepTimedOut, step.Status)
+	assert.Equal(t, StatusCompensated, state.Status)
+}
+
+func TestEngine_Timeout_Compensation(t *testing.T) {
+	shop, engine := newShopEngine(t, nil)
+	def, _ := Sequence("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve", Compensate: &Compensation{Command: "release"}},
+		Step{Name: "charge", Service: "Shop", Command: "hang", Timeout: 20 * time.Millisecond, Compensate: &Compensation{Command: "refund"}},
+	)
+	engine.Register(def)
+
+	execution, _ := engine.Start(context.Background(), "order", nil)
+	state, err := execution.Wait(context.Background())
+	assert.ErrorIs(t, err, multiplex.ErrRequestTimeout)
+	assert.Equal(t, StatusCompensated, state.Status)
+	assert.Equal(t, "charge", state.FailedStep)
+	assert.Equal(t, []string{"reserve", "refund", "release"}, shop.commands(), "timed out step must be compensated first")
+	charge, _ := state.Step("charge")
+	assert.Equal(t, StepCompensated, charge.Status)
+}
+
+func TestEngine_Cancel(t *testing.T) {
+	shop, engine := newShopEngine(t, nil)
+	shop.gate = make(chan struct{})
+	def, _ := Sequence("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve", Compensate: &Compensation{Command: "release"}},
+		Step{Name: "charge", Service: "Shop", Command: "charge"},
+	)
+	engine.Register(def)
+
+	execution, _ := engine.Start(context.Background(), "order", nil)
+	assert.Eventually(t, func() bool { return len(shop.commands()) == 1 }, time.Second, time.Millisecond)
+	execution.Cancel()
+	close(shop.gate)
+
+	state, err := execution.Wait(context.Background())
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, StatusCompensated, state.Status)
+	assert.Equal(t, []string{"reserve", "release"}, shop.commands(), "running step must finish then be compensated")
+	charge, _ := state.Step("charge")
+	assert.Equal(t, StepPending, charge.Status)
+}
+
+func TestEngine_Resume(t *testing.T) {
+	store := NewMemoryStore()
+	shop, engine := newShopEngine(t, store)
+	def, _ := Sequence("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve"},
+		Step{Name: "charge", Service: "Shop", Command: "charge"},
+		Step{Name: "ship", Service: "Shop", Command: "ship"},
+	)
+	engine.Register(def)
+	store.Save(State{
+		ID:       "w1",
+		Workflow: "order",
+		Status:   StatusRunning,
+		Steps: []StepState{
+			{Name: "reserve", Status: StepCompleted, Attempts: 1, Result: "reserve:", Completed: 1},
+			{Name: "charge", Status: StepRunning, Attempts: 1},
+			{Name: "ship", Status: StepPending},
+		},
+	})
+	store.Save(State{ID: "w2", Workflow: "order", Status: StatusCompleted})
+	store.Save(State{ID: "w3", Workflow: "legacy", Status: StatusRunning})
+
+	executions, err := engine.Resume(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, executions, 1)
+	state, err := executions[0].Wait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCompleted, state.Status)
+	assert.Equal(t, []string{"charge", "ship"}, shop.commands(), "completed steps must not be sent again")
+	charge, _ := state.Step("charge")
+	assert.Equal(t, 2, charge.Attempts)
+	ship, _ := state.Step("ship")
+	assert.Equal(t, 3, ship.Completed)
+
+	_, err = store.Load("w1")
+	assert.ErrorIs(t, err, ErrNotFound, "state of finished workflow must be deleted")
+}
+
+func TestEngine_Resume_Compensating(t *testing.T) {
+	store := NewMemoryStore()
+	shop, engine := newShopEngine(t, store)
+	def, _ := Sequence("order",
+		Step{Name: "reserve", Service: "Shop", Command: "reserve", Compensate: &Compensation{Command: "release"}},
+		Step{Name: "charge", Service: "Shop", Command: "charge", Compensate: &Compensation{Command: "refund"}},
+		Step{Name: "ship", Service: "Shop", Command: "ship"},
+	)
+	engine.Register(def)
+	store.Save(State{
+		ID:         "w1",
+		Workflow:   "order",
+		Status:     StatusCompensating,
+		FailedStep: "ship",
+		Error:      "out of stock",
+		Steps: []StepState{
+			{Name: "reserve", Status: StepCompleted, Completed: 1},
+			{Name: "charge", Status: StepCompensated, Completed: 2},
+			{Name: "ship", Status: StepFailed, Error: "out of stock"},
+		},
+	})
+
+	executions, _ := engine.Resume(context.Background())
+	assert.Len(t, executions, 1)
+	state, err := executions[0].Wait(context.Background())
+	assert.EqualError(t, err, "workflow w1 failed at step ship: out of stock")
+	assert.Equal(t, StatusCompensated, state.Status)
+	assert.Equal(t, []string{"release"}, shop.commands())
+}
+
+func TestEngine_FileStore(t *testing.T) {
+	store, _ := NewFileStore(t.TempDir())
+	_, engine := newShopEngine(t, store)
+	engine.keepFinished = true
+	def, _ := Sequence("order", Step{Name: "reserve", Service: "Shop", Command: "reserve"})
+	engine.Register(def)
+
+	execution, _ := engine.Start(context.Background(), "order", map[string]interface{}{"item": "book"})
+	execution.Wait(context.Background())
+	persisted, err := store.Load(execution.ID())
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCompleted, persisted.Status)
+	assert.Equal(t, map[string]interface{}{"reserve": "reserve:"}, persisted.Results())
+	assert.Equal(t, "book", persisted.Input["item"])
+}
+
+func newShopEngine(t *testing.T, store Store) (*shopService, *Engine) {
+	logger := diag.NewDebugLogger(10)
+	controller := multiplex.NewServiceController(logger)
+	shop := newShopService(logger)
+	shop.SetRouter(controller)
+	shop.SetWorker(4)
+	controller.Register(shop)
+	controller.Run(false)
+	t.Cleanup(func() {
+		shop.SetWorker(0)
+	})
+	return shop, NewEngine(controller.Router(), Config{Store: store, Logger: logger})
+}
+
+type shopService struct {
+	multiplex.ServiceCore
+
+	gate     chan struct{}
+	mu       sync.Mutex
+	failures map[string]int
+	calls    []string
+	received map[string]multiplex.ExecParams
+}
+
+func newShopService(logger diag.Logger) *shopService {
+	svc := &shopService{
+		failures: make(map[string]int),
+		received: make(map[string]multiplex.ExecParams),
+	}
+	svc.InitServiceCore("Shop", logger, svc.coreProcessHook)
+	return svc
+}
+
+func (s *shopService) coreProcessHook(workerID uint64, msg *multiplex.ServiceMessage) *multiplex.HookState {
+	if msg.Command == "exit" {
+		return &multiplex.HookState{Handled: false}
+	}
+	if msg.Command == "hang" {
+		return &multiplex.HookState{Handled: true}
+	}
+	s.mu.Lock()
+	s.calls = append(s.calls, msg.Command)
+	s.received[msg.Command] = msg.Params
+	failed := s.failures[msg.Command] > 0
+	if failed {
+		s.failures[msg.Command]--
+	}
+	gate := s.gate
+	s.mu.Unlock()
+	if gate != nil {
+		<-gate
+	}
+	if failed {
+		msg.ReturnError(errOutOfStock)
+		return &multiplex.HookState{Handled: true, Error: errOutOfStock}
+	}
+	msg.Return(fmt.Sprintf("%s:%v", msg.Command, msg.GetParam("item", "")))
+	return &multiplex.HookState{Handled: true}
+}
+
+func (s *shopService) commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+func (s *shopService) params(command string) multiplex.ExecParams {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received[command]
+}
@@ -0,0 +1,15 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+/*
+Package workflow orchestrates multi-step flows across multiplex services.
+A workflow is a sequence or DAG of requests sent via ServiceRouter, where each step may define
+a compensating command to undo its effect when a later step fails. State of running workflows
+can be inspected and persisted, so they can be resumed after restart.
+
+Available since v0.11.0
+*/
+package workflow
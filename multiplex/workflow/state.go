@@ -0,0 +1,175 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package workflow
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status is the state of a workflow.
+//
+// Available since v0.11.0
+type Status string
+
+const (
+	// Steps are being executed.
+	StatusRunning Status = "running"
+
+	// All steps completed.
+	StatusCompleted Status = "completed"
+
+	// A step failed, completed steps are being compensated.
+	StatusCompensating Status = "compensating"
+
+	// A step failed and all completed steps were compensated.
+	StatusCompensated Status = "compensated"
+
+	// A step failed and some completed steps could not be compensated.
+	StatusFailed Status = "failed"
+)
+
+// Return whether the workflow has finished.
+//
+// Available since v0.11.0
+func (s Status) Terminal() bool {
+	return s == StatusCompleted || s == StatusCompensated || s == StatusFailed
+}
+
+// StepStatus is the state of a step.
+//
+// Available since v0.11.0
+type StepStatus string
+
+const (
+	// The step has not started.
+	StepPending StepStatus = "pending"
+
+	// The request of the step is being processed.
+	StepRunning StepStatus = "running"
+
+	// The step returned a result.
+	StepCompleted StepStatus = "completed"
+
+	// All attempts of the step failed.
+	StepFailed StepStatus = "failed"
+
+	// All attempts of the step failed and at least one of them timed out.
+	// The request may still succeed later, so the step is compensated like a completed step.
+	StepTimedOut StepStatus = "timed_out"
+
+	// The compensation of the step is being processed.
+	StepCompensating StepStatus = "compensating"
+
+	// The compensation of the step returned a result.
+	StepCompensated StepStatus = "compensated"
+
+	// All attempts of the compensation of the step failed.
+	StepCompensationFailed StepStatus = "compensation_failed"
+)
+
+// StepError is returned when a workflow fails because of a step.
+//
+// Available since v0.11.0
+type StepError struct {
+	WorkflowID string
+	// Name of the failed step, empty if the workflow was cancelled.
+	Step string
+	Err  error
+}
+
+// Return the error message.
+//
+// Available since v0.11.0
+func (e *StepError) Error() string {
+	if e.Step == "" {
+		return fmt.Sprintf("workflow %s failed: %v", e.WorkflowID, e.Err)
+	}
+	return fmt.Sprintf("workflow %s failed at step %s: %v", e.WorkflowID, e.Step, e.Err)
+}
+
+// Return the error of the step.
+//
+// Available since v0.11.0
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// StepState is the progress of a step.
+//
+// Available since v0.11.0
+type StepState struct {
+	Name     string      `json:"name"`
+	Status   StepStatus  `json:"status"`
+	Attempts int         `json:"attempts,omitempty"`
+	Result   interface{} `json:"result,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	// Error of the last compensation attempt, if any.
+	CompensationError string `json:"compensation_error,omitempty"`
+	// Order in which the step completed, starting from 1. Steps are compensated in reverse order.
+	Completed int        `json:"completed,omitempty"`
+	Started   *time.Time `json:"started,omitempty"`
+	Finished  *time.Time `json:"finished,omitempty"`
+}
+
+// State is the progress of a workflow. It is serializable to JSON,
+// results and input must be JSON serializable for the state to be persisted by a FileStore.
+//
+// Available since v0.11.0
+type State struct {
+	ID       string                 `json:"id"`
+	Workflow string                 `json:"workflow"`
+	Status   Status                 `json:"status"`
+	Input    map[string]interface{} `json:"input,omitempty"`
+	Steps    []StepState            `json:"steps"`
+	// Step causing the workflow to fail, empty if the workflow was cancelled.
+	FailedStep string    `json:"failed_step,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Started    time.Time `json:"started"`
+	Updated    time.Time `json:"updated"`
+}
+
+// Return the progress of the step with specified name.
+//
+// Available since v0.11.0
+func (s State) Step(name string) (StepState, bool) {
+	for _, step := range s.Steps {
+		if step.Name == name {
+			return step, true
+		}
+	}
+	return StepState{}, false
+}
+
+// Return results of completed steps by step name, including steps compensated later.
+//
+// Available since v0.11.0
+func (s State) Results() map[string]interface{} {
+	results := make(map[string]interface{})
+	for _, step := range s.Steps {
+		if step.Completed > 0 {
+			results[step.Name] = step.Result
+		}
+	}
+	return results
+}
+
+// Return copy of the state not sharing steps with the original.
+func (s State) clone() State {
+	s.Steps = append([]StepState(nil), s.Steps...)
+	return s
+}
+
+// Return pointer to the progress of the step with specified name.
+func (s *State) step(name string) *StepState {
+	for i := range s.Steps {
+		if s.Steps[i].Name == name {
+			return &s.Steps[i]
+		}
+	}
+	return nil
+}
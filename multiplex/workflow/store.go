@@ -0,0 +1,210 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned when a Store has no state for a workflow.
+//
+// Available since v0.11.0
+var ErrNotFound = errors.New("workflow not found")
+
+// Store persists states of workflows. Implementations must be safe for concurrent use.
+//
+// Available since v0.11.0
+type Store interface {
+	// Create or replace the state of a workflow.
+	Save(state State) error
+	// Return the state of a workflow, or ErrNotFound.
+	Load(id string) (State, error)
+	// Return states of all workflows.
+	List() ([]State, error)
+	// Remove the state of a workflow.
+	Delete(id string) error
+}
+
+// Struct MemoryStore is an in-memory Store, mostly useful for inspection and tests.
+//
+// Available since v0.11.0
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+// Return new empty MemoryStore.
+//
+// Available since v0.11.0
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		states: make(map[string]State),
+	}
+}
+
+// Create or replace the state of a workflow.
+//
+// Available since v0.11.0
+func (s *MemoryStore) Save(state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.ID] = state.clone()
+	return nil
+}
+
+// Return the state of a workflow, or ErrNotFound.
+//
+// Available since v0.11.0
+func (s *MemoryStore) Load(id string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, found := s.states[id]
+	if !found {
+		return State{}, ErrNotFound
+	}
+	return state.clone(), nil
+}
+
+// Return states of all workflows ordered by ID.
+//
+// Available since v0.11.0
+func (s *MemoryStore) List() ([]State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states := make([]State, 0, len(s.states))
+	for _, state := range s.states {
+		states = append(states, state.clone())
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].ID < states[j].ID
+	})
+	return states, nil
+}
+
+// Remove the state of a workflow.
+//
+// Available since v0.11.0
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, id)
+	return nil
+}
+
+// Struct FileStore is a Store keeping the state of each workflow in a JSON file of a directory.
+// Files are replaced atomically, so a crash never leaves a partially written state.
+// Input and step results are decoded by encoding/json when loaded, so their types may differ
+// from the saved ones: numbers become float64, structs become map[string]interface{}
+// and slices become []interface{}.
+//
+// Available since v0.11.0
+type FileStore struct {
+	dir string
+}
+
+// Return new FileStore using the directory at path, which is created if needed.
+//
+// Available since v0.11.0
+func NewFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: path}, nil
+}
+
+// Create or replace the state of a workflow.
+//
+// Available since v0.11.0
+func (s *FileStore) Save(state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), s.path(state.ID))
+}
+
+// Return the state of a workflow, or ErrNotFound.
+//
+// Available since v0.11.0
+func (s *FileStore) Load(id string) (State, error) {
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return State{}, ErrNotFound
+	}
+	if err != nil {
+		return State{}, err
+	}
+	var state State
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+// Return states of all workflows ordered by ID.
+//
+// Available since v0.11.0
+func (s *FileStore) List() ([]State, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var states []State
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		state, err := s.Load(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].ID < states[j].ID
+	})
+	return states, nil
+}
+
+// Remove the state of a workflow.
+//
+// Available since v0.11.0
+func (s *FileStore) Delete(id string) error {
+	err := os.Remove(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Return path of the file storing the state of a workflow.
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id)+".json")
+}
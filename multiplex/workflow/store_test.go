@@ -0,0 +1,83 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	testStore(t, store)
+}
+
+func TestFileStore_JSONTypes(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	type receipt struct {
+		Total int `json:"total"`
+	}
+	state := State{
+		ID:       "a",
+		Workflow: "order",
+		Input:    map[string]interface{}{"quantity": 2},
+		Steps:    []StepState{{Name: "charge", Result: receipt{Total: 30}}},
+	}
+	assert.NoError(t, store.Save(state))
+	loaded, err := store.Load("a")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), loaded.Input["quantity"])
+	assert.Equal(t, map[string]interface{}{"total": float64(30)}, loaded.Steps[0].Result)
+}
+
+func testStore(t *testing.T, store Store) {
+	_, err := store.Load("missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	state := State{
+		ID:       "b",
+		Workflow: "order",
+		Status:   StatusRunning,
+		Input:    map[string]interface{}{"order_id": "o1"},
+		Steps: []StepState{
+			{Name: "reserve", Status: StepCompleted, Attempts: 1, Result: "r1", Completed: 1},
+			{Name: "charge", Status: StepPending},
+		},
+		Started: started,
+		Updated: started,
+	}
+	assert.NoError(t, store.Save(state))
+	assert.NoError(t, store.Save(State{ID: "a", Workflow: "order", Status: StatusCompleted}))
+
+	loaded, err := store.Load("b")
+	assert.NoError(t, err)
+	assert.Equal(t, state, loaded)
+
+	state.Steps[1].Status = StepRunning
+	loaded, _ = store.Load("b")
+	assert.Equal(t, StepPending, loaded.Steps[1].Status, "stored state must not share steps")
+
+	states, err := store.List()
+	assert.NoError(t, err)
+	assert.Len(t, states, 2)
+	assert.Equal(t, "a", states[0].ID)
+	assert.Equal(t, "b", states[1].ID)
+
+	assert.NoError(t, store.Delete("a"))
+	assert.NoError(t, store.Delete("a"))
+	states, _ = store.List()
+	assert.Len(t, states, 1)
+}
@@ -6,7 +6,7 @@
 
 /*
 Package opx provides generics function to handle common pattern that are not
-provided by Go lang standard libraries.
+provided by Go lang standard libraries, such as retrying fallible calls with backoff.
 Available since v0.3.0
 */
 package opx
@@ -0,0 +1,204 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package opx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/tforce-io/tf-golib/random/pseudorng"
+)
+
+// Number of attempts made by Retry when RetryConfig.MaxAttempts is zero.
+// Available since v0.11.0
+const DefaultRetryAttempts = 3
+
+// ErrMaxAttempts is the reason of RetryError when all attempts failed.
+// Available since v0.11.0
+var ErrMaxAttempts = errors.New("max attempts reached")
+
+// ErrMaxElapsed is the reason of RetryError when the next attempt would start after max elapsed time.
+// Available since v0.11.0
+var ErrMaxElapsed = errors.New("max elapsed time reached")
+
+// RetryError is returned when Retry gives up. It wraps the error of the last attempt,
+// errors.Is also matches its Reason, which is ErrMaxAttempts, ErrMaxElapsed or the error of the context.
+// Available since v0.11.0
+type RetryError struct {
+	Attempts int
+	Err      error
+	Reason   error
+}
+
+// Return the error message.
+// Available since v0.11.0
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("gave up after %d attempts (%v): %v", e.Attempts, e.Reason, e.Err)
+}
+
+// Return the error of the last attempt.
+// Available since v0.11.0
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Report whether target matches the reason Retry gave up.
+// Available since v0.11.0
+func (e *RetryError) Is(target error) bool {
+	return errors.Is(e.Reason, target)
+}
+
+// Backoff returns the delay before the next attempt, given the number of failed attempts
+// starting from 1 and the previous delay, which is zero after the first attempt.
+// Available since v0.11.0
+type Backoff func(attempt int, previous time.Duration) time.Duration
+
+// RetryConfig stores options of Retry.
+// Available since v0.11.0
+type RetryConfig struct {
+	// Maximum number of attempts including the first one. Zero means DefaultRetryAttempts, negative means unlimited.
+	MaxAttempts int
+	// Maximum time since the first attempt for the next attempt to start. Non-positive means unlimited.
+	MaxElapsed time.Duration
+	// Delay between attempts. Nil means retrying immediately.
+	Backoff Backoff
+	// Return whether a failed attempt should be retried. Nil means all errors are retried.
+	Retryable func(err error) bool
+	// Called before waiting for the next attempt.
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// Call fn until it returns nil error or Retry gives up.
+// RetryError is returned when attempts or time are exhausted, or ctx is done while waiting.
+// Errors not retryable are returned as is.
+// Available since v0.11.0
+func Retry(ctx context.Context, config RetryConfig, fn func() error) error {
+	_, err := Retry1(ctx, config, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
+}
+
+// Call fn until it returns nil error then return its value, or until Retry1 gives up.
+// RetryError is returned when attempts or time are exhausted, or ctx is done while waiting.
+// Errors not retryable are returned as is.
+// Available since v0.11.0
+func Retry1[T any](ctx context.Context, config RetryConfig, fn func() (T, error)) (T, error) {
+	maxAttempts := config.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultRetryAttempts
+	}
+	start := time.Now()
+	var zero T
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		value, err := fn()
+		if err == nil {
+			return value, nil
+		}
+		if config.Retryable != nil && !config.Retryable(err) {
+			return zero, err
+		}
+		if maxAttempts > 0 && attempt >= maxAttempts {
+			return zero, &RetryError{Attempts: attempt, Err: err, Reason: ErrMaxAttempts}
+		}
+		if config.Backoff != nil {
+			delay = config.Backoff(attempt, delay)
+		}
+		// Compare against the remaining time, as adding a huge delay to elapsed time overflows.
+		if config.MaxElapsed > 0 && delay > config.MaxElapsed-time.Since(start) {
+			return zero, &RetryError{Attempts: attempt, Err: err, Reason: ErrMaxElapsed}
+		}
+		if config.OnRetry != nil {
+			config.OnRetry(attempt, err, delay)
+		}
+		if ctxErr := sleepContext(ctx, delay); ctxErr != nil {
+			return zero, &RetryError{Attempts: attempt, Err: err, Reason: ctxErr}
+		}
+	}
+}
+
+// Return a Backoff waiting delay between attempts.
+// Available since v0.11.0
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(attempt int, previous time.Duration) time.Duration {
+		return delay
+	}
+}
+
+// Return a Backoff waiting initial after the first attempt then step longer after each attempt,
+// up to max. Non-positive max means unlimited.
+// Available since v0.11.0
+func LinearBackoff(initial, step, max time.Duration) Backoff {
+	return func(attempt int, previous time.Duration) time.Duration {
+		return capDelay(float64(initial)+float64(step)*float64(attempt-1), max)
+	}
+}
+
+// Return a Backoff waiting initial after the first attempt then factor times longer after each attempt,
+// up to max. Non-positive max means unlimited. Factor less than 1 means 2.
+// Available since v0.11.0
+func ExponentialBackoff(initial time.Duration, factor float64, max time.Duration) Backoff {
+	if factor < 1 {
+		factor = 2
+	}
+	return func(attempt int, previous time.Duration) time.Duration {
+		return capDelay(float64(initial)*math.Pow(factor, float64(attempt-1)), max)
+	}
+}
+
+// Return a Backoff waiting a random delay between base and three times the previous delay, up to max.
+// Non-positive max means unlimited. Randomness spreads retries of concurrent callers over time.
+// Available since v0.11.0
+func DecorrelatedJitterBackoff(base, max time.Duration) Backoff {
+	return func(attempt int, previous time.Duration) time.Duration {
+		if base <= 0 {
+			return 0
+		}
+		if previous < base {
+			previous = base
+		}
+		upper := capDelay(float64(previous)*3, 0)
+		if upper < math.MaxInt64 {
+			upper++
+		}
+		delay := time.Duration(pseudorng.Int63r(int64(base), int64(upper)))
+		return capDelay(float64(delay), max)
+	}
+}
+
+// Return delay limited to max and to the largest time.Duration. Non-positive max means unlimited.
+func capDelay(delay float64, max time.Duration) time.Duration {
+	if delay < 0 {
+		return 0
+	}
+	if max > 0 && delay > float64(max) {
+		return max
+	}
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
+
+// Wait for delay. Return the error of ctx if it is done before that.
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2025 T-Force I/O
+//
+// TF GoLib is licensed under the MIT license.
+// You should receive a copy of MIT along with this software.
+// If not, see <https://opensource.org/license/mit>
+
+package opx
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errRetryTest = errors.New("failed")
+
+func TestRetry(t *testing.T) {
+	t.Run("returns nil when an attempt succeeds", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), RetryConfig{}, func() error {
+			calls++
+			if calls < 3 {
+				return errRetryTest
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	})
+	t.Run("gives up after default attempts", func(t *testing.T) {
+		calls := 0
+		err := Retry(context.Background(), RetryConfig{}, func() error {
+			calls++
+			return errRetryTest
+		})
+		assert.ErrorIs(t, err, errRetryTest)
+		assert.ErrorIs(t, err, ErrMaxAttempts)
+		var retryErr *RetryError
+		assert.True(t, errors.As(err, &retryErr))
+		assert.Equal(t, DefaultRetryAttempts, retryErr.Attempts)
+		assert.Equal(t, DefaultRetryAttempts, calls)
+		assert.EqualError(t, err, "gave up after 3 attempts (max attempts reached): failed")
+	})
+}
+
+func TestRetry1(t *testing.T) {
+	t.Run("returns value of the successful attempt", func(t *testing.T) {
+		calls := 0
+		value, err := Retry1(context.Background(), RetryConfig{MaxAttempts: 5}, func() (int, error) {
+			calls++
+			if calls < 4 {
+				return 0, errRetryTest
+			}
+			return calls * 10, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 40, value)
+	})
+	t.Run("returns error not retryable as is", func(t *testing.T) {
+		fatal := errors.New("fatal")
+		calls := 0
+		_, err := Retry1(context.Background(), RetryConfig{
+			MaxAttempts: -1,
+			Retryable: func(err error) bool {
+				return !errors.Is(err, fatal)
+			},
+		}, func() (int, error) {
+			calls++
+			if calls == 3 {
+				return 0, fatal
+			}
+			return 0, errRetryTest
+		})
+		assert.Equal(t, fatal, err)
+		assert.Equal(t, 3, calls)
+	})
+	t.Run("calls OnRetry with backoff delays", func(t *testing.T) {
+		var attempts []int
+		var delays []time.Duration
+		_, err := Retry1(context.Background(), RetryConfig{
+			MaxAttempts: 4,
+			Backoff:     LinearBackoff(time.Millisecond, time.Millisecond, 0),
+			OnRetry: func(attempt int, err error, delay time.Duration) {
+				assert.Equal(t, errRetryTest, err)
+				attempts = append(attempts, attempt)
+				delays = append(delays, delay)
+			},
+		}, func() (string, error) {
+			return "", errRetryTest
+		})
+		assert.ErrorIs(t, err, ErrMaxAttempts)
+		assert.Equal(t, []int{1, 2, 3}, attempts)
+		assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}, delays)
+	})
+	t.Run("stops when the next attempt exceeds max elapsed time", func(t *testing.T) {
+		calls := 0
+		_, err := Retry1(context.Background(), RetryConfig{
+			MaxAttempts: -1,
+			MaxElapsed:  50 * time.Millisecond,
+			Backoff:     ConstantBackoff(20 * time.Millisecond),
+		}, func() (int, error) {
+			calls++
+			return 0, errRetryTest
+		})
+		assert.ErrorIs(t, err, ErrMaxElapsed)
+		assert.ErrorIs(t, err, errRetryTest)
+		assert.GreaterOrEqual(t, calls, 2)
+		assert.LessOrEqual(t, calls, 3, "attempt starting after max elapsed time must not be made")
+	})
+	t.Run("stops before uncapped delay exceeds max elapsed time", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		calls := 0
+		_, err := Retry1(ctx, RetryConfig{
+			MaxAttempts: -1,
+			MaxElapsed:  time.Minute,
+			Backoff:     ExponentialBackoff(math.MaxInt64, 2, 0),
+		}, func() (int, error) {
+			calls++
+			return 0, errRetryTest
+		})
+		assert.ErrorIs(t, err, ErrMaxElapsed)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("stops when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		_, err := Retry1(ctx, RetryConfig{
+			MaxAttempts: -1,
+			Backoff:     ConstantBackoff(time.Hour),
+			OnRetry: func(attempt int, err error, delay time.Duration) {
+				cancel()
+			},
+		}, func() (int, error) {
+			calls++
+			return 0, errRetryTest
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, errRetryTest)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestConstantBackoff(t *testing.T) {
+	backoff := ConstantBackoff(time.Second)
+	assert.Equal(t, time.Second, backoff(1, 0))
+	assert.Equal(t, time.Second, backoff(10, time.Second))
+}
+
+func TestLinearBackoff(t *testing.T) {
+	backoff := LinearBackoff(time.Second, 2*time.Second, 6*time.Second)
+	assert.Equal(t, time.Second, backoff(1, 0))
+	assert.Equal(t, 3*time.Second, backoff(2, time.Second))
+	assert.Equal(t, 5*time.Second, backoff(3, 3*time.Second))
+	assert.Equal(t, 6*time.Second, backoff(4, 5*time.Second))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, 2, time.Second)
+	assert.Equal(t, 100*time.Millisecond, backoff(1, 0))
+	assert.Equal(t, 200*time.Millisecond, backoff(2, 0))
+	assert.Equal(t, 800*time.Millisecond, backoff(4, 0))
+	assert.Equal(t, time.Second, backoff(5, 0))
+
+	unlimited := ExponentialBackoff(time.Second, 0, 0)
+	assert.Equal(t, 4*time.Second, unlimited(3, 0))
+	assert.Equal(t, time.Duration(math.MaxInt64), unlimited(100, 0))
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	backoff := DecorrelatedJitterBackoff(10*time.Millisecond, time.Second)
+	previous := time.Duration(0)
+	for attempt := 1; attempt <= 100; attempt++ {
+		delay := backoff(attempt, previous)
+		lower, upper := 10*time.Millisecond, 3*previous
+		if upper < 30*time.Millisecond {
+			upper = 30 * time.Millisecond
+		}
+		if upper > time.Second {
+			upper = time.Second
+		}
+		assert.GreaterOrEqual(t, delay, lower)
+		assert.LessOrEqual(t, delay, upper)
+		previous = delay
+	}
+	assert.Equal(t, time.Duration(0), DecorrelatedJitterBackoff(0, time.Second)(1, 0))
+	assert.LessOrEqual(t, DecorrelatedJitterBackoff(time.Second, 0)(1, time.Duration(math.MaxInt64)), time.Duration(math.MaxInt64))
+}